@@ -0,0 +1,269 @@
+package main
+
+import (
+	"MikaBooM/internal/api"
+	"MikaBooM/internal/config"
+	"MikaBooM/internal/history"
+	"MikaBooM/internal/resource"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// command 子命令
+type command struct {
+	usage string
+	run   func(env *commandEnv, args []string) error
+}
+
+// commandEnv 子命令使用的配置
+type commandEnv struct {
+	cfgPath string
+	cfg     *config.Config
+}
+
+// client 返回连接运行中实例的客户端
+func (env *commandEnv) client() (*api.Client, error) {
+	if !env.cfg.API.Enabled {
+		return nil, fmt.Errorf("控制接口未启用，请在 %s 中设置 api.enabled 和 api.token", env.cfgPath)
+	}
+	return api.NewClient(env.cfg.API.Listen, env.cfg.API.Token), nil
+}
+
+// remote 将只需要客户端的子命令包装为 command.run
+func remote(fn func(client *api.Client, args []string) error) func(*commandEnv, []string) error {
+	return func(env *commandEnv, args []string) error {
+		client, err := env.client()
+		if err != nil {
+			return err
+		}
+		return fn(client, args)
+	}
+}
+
+// setUsage set 子命令的用法
+const setUsage = "set <cpu|memory|network|disk> <阈值|default>"
+
+var commands = map[string]command{
+	"status": {"status", remote(runStatus)},
+	"pause":  {"pause [cpu|memory|network|disk]", remote(runPause)},
+	"resume": {"resume [cpu|memory|network|disk]", remote(runResume)},
+	"set":    {setUsage, remote(runSet)},
+	"stop":   {"stop", remote(runStop)},
+	"report": {"report [--since 24h]", runReport},
+}
+
+// commandOrder 帮助信息中子命令的显示顺序
+var commandOrder = []string{"status", "pause", "resume", "set", "stop", "report"}
+
+// resourceAliases 子命令中资源名称的简写
+var resourceAliases = map[string]string{
+	"mem": "memory",
+	"net": "network",
+	"all": "",
+}
+
+// runCommand 执行子命令，不会启动新的监控循环
+func runCommand(args []string) error {
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("未知的命令: %s (可用命令: %s)", args[0], strings.Join(commandOrder, ", "))
+	}
+
+	cfgPath, err := config.FindConfigFile(*configFile)
+	if err != nil {
+		return fmt.Errorf("查找配置文件失败: %w", err)
+	}
+	if !config.ConfigExists(cfgPath) {
+		return fmt.Errorf("配置文件不存在: %s", cfgPath)
+	}
+
+	cfg, err := config.LoadConfig(cfgPath)
+	if err != nil {
+		return err
+	}
+
+	return cmd.run(&commandEnv{cfgPath: cfgPath, cfg: cfg}, args[1:])
+}
+
+func resourceArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	name := strings.ToLower(args[0])
+	if alias, ok := resourceAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+func runStatus(client *api.Client, args []string) error {
+	st, err := client.Status()
+	if err != nil {
+		return err
+	}
+
+	color.New(color.FgCyan, color.Bold).Printf("MikaBooM v%s", st.Version)
+	if !st.Time.IsZero() {
+		fmt.Printf("  (更新于 %s)", st.Time.Format("15:04:05"))
+	}
+	fmt.Println()
+
+	for _, r := range st.Resources {
+		fmt.Printf("  %-6s 占用 %6.1f%-4s 其他 %6.1f%-4s 阈值 %d%s",
+			r.Label, r.Usage, r.Unit, r.OtherUsage, r.Unit, r.Threshold, r.Unit)
+
+		switch {
+		case !r.HasWorker:
+			color.New(color.FgHiBlack).Print("  工作器: 无")
+		case r.Paused:
+			color.New(color.FgHiBlack).Print("  工作器: 已暂停")
+		case r.Running:
+			color.New(color.FgGreen).Printf("  工作器: 运行中 (%s)", r.Detail)
+		default:
+			color.New(color.FgYellow).Print("  工作器: 已停止")
+		}
+		fmt.Println()
+	}
+
+	for _, note := range st.Notes {
+		fmt.Printf("  %s\n", note)
+	}
+	return nil
+}
+
+func runPause(client *api.Client, args []string) error {
+	name := resourceArg(args)
+	if err := client.Pause(name); err != nil {
+		return err
+	}
+	color.Green("✓ 已暂停%s", describeTarget(name))
+	return nil
+}
+
+func runResume(client *api.Client, args []string) error {
+	name := resourceArg(args)
+	if err := client.Resume(name); err != nil {
+		return err
+	}
+	color.Green("✓ 已恢复%s", describeTarget(name))
+	return nil
+}
+
+func runSet(client *api.Client, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("用法: MikaBooM %s", setUsage)
+	}
+
+	name := resourceArg(args)
+	if name == "" {
+		return fmt.Errorf("set 必须指定资源")
+	}
+
+	if args[1] == "default" {
+		if err := client.ClearThreshold(name); err != nil {
+			return err
+		}
+		color.Green("✓ %s 阈值已恢复为配置文件中的值", name)
+		return nil
+	}
+
+	value, err := strconv.Atoi(args[1])
+	if err != nil {
+		return fmt.Errorf("无效的阈值: %s", args[1])
+	}
+	if err := client.SetThreshold(name, value); err != nil {
+		return err
+	}
+	color.Green("✓ %s 阈值已设置为 %d", name, value)
+	return nil
+}
+
+func runStop(client *api.Client, args []string) error {
+	if err := client.Quit(); err != nil {
+		return err
+	}
+	color.Green("✓ 已请求实例退出")
+	return nil
+}
+
+func describeTarget(name string) string {
+	if name == "" {
+		return "所有工作器"
+	}
+	return " " + name + " 工作器"
+}
+
+// runReport 读取历史记录并输出统计，不需要实例正在运行
+func runReport(env *commandEnv, args []string) error {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	since := fs.Duration("since", 24*time.Hour, "统计最近多长时间，例如 24h、7h30m")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := historyPath(env.cfg, env.cfgPath)
+	entries, err := history.Read(path, time.Now().Add(-*since))
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		if !env.cfg.History.Enabled {
+			return fmt.Errorf("没有历史记录，请在 %s 中设置 history.enabled: true", env.cfgPath)
+		}
+		return fmt.Errorf("最近 %s 内没有历史记录 (%s)", *since, path)
+	}
+
+	color.New(color.FgCyan, color.Bold).Printf("📊 最近 %s 的占用统计\n", *since)
+	fmt.Printf("  %s ~ %s\n", entries[0].Time.Local().Format("2006-01-02 15:04"), entries[len(entries)-1].Time.Local().Format("2006-01-02 15:04"))
+	fmt.Println()
+
+	for _, s := range history.Summarize(entries) {
+		color.New(color.FgHiCyan, color.Bold).Printf("%s (%s)  记录时长 %s\n", s.Resource, s.Unit, formatSeconds(s.Seconds))
+		fmt.Printf("  系统占用   平均 %.1f  p50 %.1f  p95 %.1f  p99 %.1f  最大 %.1f\n", s.Mean, s.P50, s.P95, s.P99, s.Max)
+		fmt.Printf("  其他程序   平均 %.1f\n", s.OtherMean)
+		fmt.Printf("  MikaBooM   平均 %.1f", s.WorkerMean)
+		if s.Mean > 0 {
+			fmt.Printf("  (占系统占用 %.1f%%)", s.WorkerMean/s.Mean*100)
+		}
+		fmt.Println()
+		fmt.Printf("  超过阈值   %s (%.1f%%)\n", formatSeconds(s.AboveSeconds), ratio(s.AboveSeconds, s.Seconds))
+		fmt.Printf("  工作器运行 %s (%.1f%%)\n", formatSeconds(s.ActiveSeconds), ratio(s.ActiveSeconds, s.Seconds))
+		if added := describeAdded(s); added != "" {
+			fmt.Printf("  额外负载   %s\n", added)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// describeAdded 将工作器占用对时间的积分换算为便于理解的量
+func describeAdded(s history.Summary) string {
+	hours := s.WorkerTotal / 3600
+	switch s.Resource {
+	case resource.CPU:
+		return fmt.Sprintf("%.2f 核·小时 (按记录时的 %g 核计算)", s.CapacityTotal/3600, s.Capacity)
+	case resource.Memory:
+		return fmt.Sprintf("%.2f %%·小时", hours)
+	case resource.Network:
+		return fmt.Sprintf("%.2f GB 发送流量", s.WorkerTotal/8/1000)
+	case resource.Disk:
+		return fmt.Sprintf("%.2f GB 读写量", s.WorkerTotal/1024)
+	}
+	return ""
+}
+
+func ratio(part, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return part / total * 100
+}
+
+func formatSeconds(seconds float64) string {
+	return (time.Duration(seconds) * time.Second).Round(time.Minute).String()
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"MikaBooM/internal/config"
+)
+
+// Client 连接正在运行的实例的控制接口
+type Client struct {
+	base  string
+	token string
+	http  *http.Client
+}
+
+// NewClient 创建客户端，addr 与配置中的 api.listen 格式相同
+func NewClient(addr, token string) *Client {
+	c := &Client{
+		base:  "http://" + addr,
+		token: token,
+		http:  &http.Client{Timeout: 10 * time.Second},
+	}
+
+	if strings.HasPrefix(addr, config.APIUnixPrefix) {
+		path := strings.TrimPrefix(addr, config.APIUnixPrefix)
+		c.base = "http://mikaboom"
+		c.http.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		}
+	}
+	return c
+}
+
+// Status 获取运行状态
+func (c *Client) Status() (*Status, error) {
+	var st Status
+	if err := c.do(http.MethodGet, "/v1/status", nil, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// Pause 暂停工作器，name 为空时暂停所有工作器
+func (c *Client) Pause(name string) error {
+	return c.post("/v1/pause", ResourceRequest{Resource: name})
+}
+
+// Resume 恢复工作器，name 为空时恢复所有工作器
+func (c *Client) Resume(name string) error {
+	return c.post("/v1/resume", ResourceRequest{Resource: name})
+}
+
+// SetThreshold 设置运行时阈值
+func (c *Client) SetThreshold(name string, value int) error {
+	return c.post("/v1/threshold", ThresholdRequest{Resource: name, Value: &value})
+}
+
+// ClearThreshold 取消运行时阈值，恢复使用配置文件中的值
+func (c *Client) ClearThreshold(name string) error {
+	return c.post("/v1/threshold", ThresholdRequest{Resource: name})
+}
+
+// Quit 请求实例退出
+func (c *Client) Quit() error {
+	return c.post("/v1/quit", nil)
+}
+
+func (c *Client) post(path string, body interface{}) error {
+	var resp Response
+	return c.do(http.MethodPost, path, body, &resp)
+}
+
+func (c *Client) do(method, path string, body, out interface{}) error {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, c.base+path, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("连接运行中的实例失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var r Response
+		if json.NewDecoder(resp.Body).Decode(&r) == nil && r.Error != "" {
+			return fmt.Errorf("%s", r.Error)
+		}
+		return fmt.Errorf("请求失败: %s", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	return nil
+}
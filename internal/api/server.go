@@ -0,0 +1,300 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"MikaBooM/internal/config"
+	"MikaBooM/internal/controller"
+	"MikaBooM/internal/resource"
+	"MikaBooM/internal/version"
+	"MikaBooM/internal/worker"
+)
+
+// Options 控制接口依赖
+type Options struct {
+	Controller *controller.Controller
+	Token      string
+	Quit       func() // 退出请求，应与托盘“退出”菜单走同一路径
+}
+
+// Server 本地控制接口
+type Server struct {
+	ctrl     *controller.Controller
+	token    string
+	quit     func()
+	listener net.Listener
+	server   *http.Server
+	socket   string // Unix socket 文件路径，关闭时删除
+}
+
+// Listen 在 addr 上启动控制接口，addr 为 host:port 或 unix:/path/to/socket
+func Listen(addr string, opts Options) (*Server, error) {
+	s := &Server{
+		ctrl:  opts.Controller,
+		token: opts.Token,
+		quit:  opts.Quit,
+	}
+
+	listener, err := s.listen(addr)
+	if err != nil {
+		return nil, fmt.Errorf("启动控制接口失败: %w", err)
+	}
+	s.listener = listener
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/status", s.handleStatus)
+	mux.HandleFunc("/v1/pause", s.handlePause)
+	mux.HandleFunc("/v1/resume", s.handleResume)
+	mux.HandleFunc("/v1/threshold", s.handleThreshold)
+	mux.HandleFunc("/v1/quit", s.handleQuit)
+
+	s.server = &http.Server{
+		Handler:           s.authorize(mux),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("控制接口异常退出: %v", err)
+		}
+	}()
+	return s, nil
+}
+
+func (s *Server) listen(addr string) (net.Listener, error) {
+	if !strings.HasPrefix(addr, config.APIUnixPrefix) {
+		return net.Listen("tcp", addr)
+	}
+
+	path := strings.TrimPrefix(addr, config.APIUnixPrefix)
+	// 上次异常退出可能留下 socket 文件，只删除 socket 类型的文件
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(path)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	s.socket = path
+	return listener, nil
+}
+
+// Addr 返回实际监听的地址
+func (s *Server) Addr() string {
+	if s.socket != "" {
+		return config.APIUnixPrefix + s.socket
+	}
+	return s.listener.Addr().String()
+}
+
+// Close 停止控制接口
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	err := s.server.Shutdown(ctx)
+	if s.socket != "" {
+		os.Remove(s.socket)
+	}
+	return err
+}
+
+// authorize 校验 Authorization: Bearer <token>
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if s.token == "" || !strings.HasPrefix(auth, "Bearer ") ||
+			subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(s.token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, Response{Error: "未授权"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, Response{Error: "只支持 GET"})
+		return
+	}
+	writeJSON(w, http.StatusOK, s.status())
+}
+
+func (s *Server) status() Status {
+	snap, _ := s.ctrl.Latest()
+
+	st := Status{
+		Time:    snap.Time,
+		Version: version.GetVersion(),
+		Notes:   snap.Notes,
+	}
+	for _, res := range s.ctrl.Registry().All() {
+		rs := ResourceStatus{
+			Name:   res.Name,
+			Label:  res.Label,
+			Unit:   res.Unit,
+			Paused: s.ctrl.IsPaused(res.Name),
+		}
+		if state, ok := snap.Get(res.Name); ok {
+			rs.Usage = state.Usage
+			rs.OtherUsage = state.OtherUsage
+			rs.WorkerUsage = state.WorkerUsage
+			rs.Threshold = state.Threshold
+			rs.Target = state.Target
+		}
+
+		if res.Loader != nil {
+			rs.HasWorker = true
+			rs.Running = res.Loader.IsRunning()
+			if reporter, ok := res.Loader.(worker.Reporter); ok {
+				rs.Detail = reporter.Status()
+			}
+			if l, ok := res.Loader.(interface{ GetIntensity() int32 }); ok {
+				intensity := l.GetIntensity()
+				rs.Intensity = &intensity
+			}
+			if l, ok := res.Loader.(interface{ GetAllocatedSize() int64 }); ok {
+				allocated := l.GetAllocatedSize()
+				rs.AllocatedBytes = &allocated
+			}
+		}
+		st.Resources = append(st.Resources, rs)
+	}
+	return st
+}
+
+func (s *Server) handlePause(w http.ResponseWriter, r *http.Request) {
+	s.handleWorkers(w, r, "暂停工作器", s.ctrl.Pause)
+}
+
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	s.handleWorkers(w, r, "恢复工作器", s.ctrl.Resume)
+}
+
+// handleWorkers 对指定资源或所有带工作器的资源执行 fn
+func (s *Server) handleWorkers(w http.ResponseWriter, r *http.Request, action string, fn func(string) error) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, Response{Error: "只支持 POST"})
+		return
+	}
+
+	var req ResourceRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	names := []string{req.Resource}
+	if req.Resource == "" {
+		names = nil
+		for _, res := range s.ctrl.Registry().All() {
+			if res.Loader != nil {
+				names = append(names, res.Name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		if err := fn(name); err != nil {
+			writeJSON(w, http.StatusNotFound, Response{Error: err.Error()})
+			return
+		}
+		slog.Info("控制接口: "+action, "resource", name)
+	}
+	writeJSON(w, http.StatusOK, Response{OK: true})
+}
+
+func (s *Server) handleThreshold(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, Response{Error: "只支持 POST"})
+		return
+	}
+
+	var req ThresholdRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	res, ok := s.ctrl.Registry().Get(req.Resource)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, Response{Error: fmt.Sprintf("未知的资源: %s", req.Resource)})
+		return
+	}
+
+	if req.Value == nil {
+		s.ctrl.ClearThreshold(res.Name)
+		slog.Info("控制接口: 恢复配置阈值", "resource", res.Name)
+		writeJSON(w, http.StatusOK, Response{OK: true})
+		return
+	}
+
+	if err := validateThreshold(res, *req.Value); err != nil {
+		writeJSON(w, http.StatusBadRequest, Response{Error: err.Error()})
+		return
+	}
+	if err := s.ctrl.SetThreshold(res.Name, *req.Value); err != nil {
+		writeJSON(w, http.StatusNotFound, Response{Error: err.Error()})
+		return
+	}
+	slog.Info("控制接口: 设置阈值", "resource", res.Name, "threshold", *req.Value)
+	writeJSON(w, http.StatusOK, Response{OK: true})
+}
+
+func (s *Server) handleQuit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, Response{Error: "只支持 POST"})
+		return
+	}
+
+	slog.Info("控制接口: 请求退出")
+	writeJSON(w, http.StatusOK, Response{OK: true})
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	if s.quit != nil {
+		s.quit()
+	}
+}
+
+// validateThreshold 百分比阈值必须在 0-100 之间，其余单位不能为负数
+func validateThreshold(res *resource.Resource, value int) error {
+	if value < 0 {
+		return fmt.Errorf("%s阈值不能为负数，当前值: %d", res.Label, value)
+	}
+	if res.Unit == "%" && value > 100 {
+		return fmt.Errorf("%s阈值必须在 0-100 之间，当前值: %d", res.Label, value)
+	}
+	return nil
+}
+
+// decodeBody 解析 JSON 请求体，空请求体视为零值
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(v)
+	if err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, Response{Error: fmt.Sprintf("无效的请求: %v", err)})
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,46 @@
+package api
+
+import "time"
+
+// ResourceStatus 单个资源的状态
+type ResourceStatus struct {
+	Name           string  `json:"name"`
+	Label          string  `json:"label"`
+	Unit           string  `json:"unit"`
+	Usage          float64 `json:"usage"`
+	OtherUsage     float64 `json:"other_usage"`
+	WorkerUsage    float64 `json:"worker_usage"`
+	Threshold      int     `json:"threshold"`
+	Target         float64 `json:"target"`
+	HasWorker      bool    `json:"has_worker"`
+	Running        bool    `json:"running"`
+	Paused         bool    `json:"paused"`
+	Detail         string  `json:"detail,omitempty"`
+	Intensity      *int32  `json:"intensity,omitempty"`       // CPU 工作强度
+	AllocatedBytes *int64  `json:"allocated_bytes,omitempty"` // 内存工作器已分配字节数
+}
+
+// Status GET /v1/status 的响应
+type Status struct {
+	Time      time.Time        `json:"time"`
+	Version   string           `json:"version"`
+	Resources []ResourceStatus `json:"resources"`
+	Notes     []string         `json:"notes,omitempty"`
+}
+
+// ResourceRequest POST /v1/pause 与 /v1/resume 的请求，Resource 为空时作用于所有工作器
+type ResourceRequest struct {
+	Resource string `json:"resource"`
+}
+
+// ThresholdRequest POST /v1/threshold 的请求，Value 为空时恢复使用配置文件中的阈值
+type ThresholdRequest struct {
+	Resource string `json:"resource"`
+	Value    *int   `json:"value"`
+}
+
+// Response 操作类请求的响应
+type Response struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,207 @@
+package cgroup
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	procCgroup    = "/proc/self/cgroup"
+	procMountInfo = "/proc/self/mountinfo"
+)
+
+// Group 当前进程所在的 cgroup v2
+type Group struct {
+	Dir string // cgroup 目录，例如 /sys/fs/cgroup
+}
+
+// Detect 查找当前进程所在的 cgroup v2 目录
+// 进程位于根 cgroup，或系统只使用 cgroup v1 时返回错误
+func Detect() (*Group, error) {
+	g, err := current()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(g.Dir, "memory.current")); err != nil {
+		return nil, errors.New("进程位于根 cgroup 或未启用内存控制器")
+	}
+	return g, nil
+}
+
+// current 返回当前进程所在的 cgroup v2 目录，可能为根 cgroup
+func current() (*Group, error) {
+	path, err := selfPath()
+	if err != nil {
+		return nil, err
+	}
+
+	mount, root, err := mountPoint()
+	if err != nil {
+		return nil, err
+	}
+
+	// 容器内挂载的可能只是 cgroup 树的一部分，root 为挂载的子树
+	rel := path
+	if root != "/" {
+		rel = strings.TrimPrefix(path, root)
+	}
+
+	g := &Group{Dir: filepath.Join(mount, rel)}
+	if _, err := os.Stat(filepath.Join(g.Dir, "cgroup.controllers")); err != nil {
+		return nil, fmt.Errorf("cgroup 目录不可用: %w", err)
+	}
+	return g, nil
+}
+
+// selfPath 从 /proc/self/cgroup 读取 cgroup v2 路径（0:: 开头的行）
+func selfPath() (string, error) {
+	data, err := os.ReadFile(procCgroup)
+	if err != nil {
+		return "", fmt.Errorf("读取 cgroup 信息失败: %w", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			return path, nil
+		}
+	}
+	return "", errors.New("未使用 cgroup v2")
+}
+
+// mountPoint 从 /proc/self/mountinfo 查找 cgroup2 的挂载点及挂载的子树
+func mountPoint() (string, string, error) {
+	f, err := os.Open(procMountInfo)
+	if err != nil {
+		return "", "", fmt.Errorf("读取挂载信息失败: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// 格式: id parent major:minor root mountpoint options ... - fstype source superoptions
+		fields := strings.Fields(scanner.Text())
+		for i, field := range fields {
+			if field == "-" && i+1 < len(fields) && fields[i+1] == "cgroup2" && len(fields) > 4 {
+				return fields[4], fields[3], nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", "", fmt.Errorf("读取挂载信息失败: %w", err)
+	}
+	return "", "", errors.New("未挂载 cgroup v2")
+}
+
+// MemoryLimit 返回 memory.max，未设置上限时第二个返回值为 false
+func (g *Group) MemoryLimit() (uint64, bool) {
+	value, err := g.readString("memory.max")
+	if err != nil || value == "max" {
+		return 0, false
+	}
+
+	limit, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return limit, true
+}
+
+// MemoryUsage 返回 cgroup 实际使用的内存
+// 与 docker stats 一致，memory.current 中可随时回收的非活跃文件缓存不计入
+func (g *Group) MemoryUsage() (uint64, error) {
+	current, err := g.readUint("memory.current")
+	if err != nil {
+		return 0, err
+	}
+
+	stat, err := g.readKeyed("memory.stat")
+	if err != nil {
+		return current, nil
+	}
+	if inactive := stat["inactive_file"]; inactive < current {
+		current -= inactive
+	}
+	return current, nil
+}
+
+// CPULimit 返回 cpu.max 折算的可用核心数，未设置上限时第二个返回值为 false
+func (g *Group) CPULimit() (float64, bool) {
+	value, err := g.readString("cpu.max")
+	if err != nil {
+		return 0, false
+	}
+
+	// 格式: "quota period"，quota 为 max 表示不限制
+	fields := strings.Fields(value)
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0, false
+	}
+
+	quota, err1 := strconv.ParseFloat(fields[0], 64)
+	period, err2 := strconv.ParseFloat(fields[1], 64)
+	if err1 != nil || err2 != nil || quota <= 0 || period <= 0 {
+		return 0, false
+	}
+	return quota / period, true
+}
+
+// CPUUsage 返回 cgroup 内所有进程累计消耗的 CPU 时间
+func (g *Group) CPUUsage() (time.Duration, error) {
+	stat, err := g.readKeyed("cpu.stat")
+	if err != nil {
+		return 0, err
+	}
+
+	usec, ok := stat["usage_usec"]
+	if !ok {
+		return 0, errors.New("cpu.stat 缺少 usage_usec")
+	}
+	return time.Duration(usec) * time.Microsecond, nil
+}
+
+func (g *Group) readString(name string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(g.Dir, name))
+	if err != nil {
+		return "", fmt.Errorf("读取 %s 失败: %w", name, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func (g *Group) readUint(name string) (uint64, error) {
+	value, err := g.readString(name)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("解析 %s 失败: %w", name, err)
+	}
+	return n, nil
+}
+
+// readKeyed 读取 "key value" 格式的文件，例如 memory.stat、cpu.stat
+func (g *Group) readKeyed(name string) (map[string]uint64, error) {
+	value, err := g.readString(name)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]uint64)
+	for _, line := range strings.Split(value, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if n, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = n
+		}
+	}
+	return values, nil
+}
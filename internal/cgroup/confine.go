@@ -0,0 +1,235 @@
+package cgroup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// cpuPeriod 设置 cpu.max 时使用的周期（微秒）
+const cpuPeriod = 100000
+
+// controllers 专用 cgroup 需要启用的控制器
+var controllers = []string{"cpu", "memory"}
+
+// Join 创建（或使用已有的）cgroup，沿路径启用 cpu 和 memory 控制器，调用 setup 写入上限后再将当前进程移入
+// path 为相对路径时位于当前 cgroup 之下，以 / 开头时相对于 cgroup v2 挂载点
+// 内存控制器不支持按线程划分，因此移入的是整个进程
+// 任一步骤失败时撤销本次的修改：进程移回原 cgroup，关闭本次启用的控制器，删除本次创建的目录
+func Join(path string, setup func(*Group) error) (*Group, error) {
+	orig, err := current()
+	if err != nil {
+		return nil, err
+	}
+	mount, _, err := mountPoint()
+	if err != nil {
+		return nil, err
+	}
+
+	var dir string
+	if filepath.IsAbs(path) {
+		dir = filepath.Join(mount, path)
+	} else {
+		dir = filepath.Join(orig.Dir, path)
+	}
+	if !strings.HasPrefix(dir, mount+string(filepath.Separator)) {
+		return nil, fmt.Errorf("cgroup 路径 %s 不在 %s 之下", path, mount)
+	}
+
+	j := &joiner{orig: orig.Dir}
+	g, err := j.join(mount, dir, setup)
+	if err != nil {
+		if rerr := j.rollback(); rerr != nil {
+			return nil, fmt.Errorf("%w（撤销修改失败: %v）", err, rerr)
+		}
+		return nil, err
+	}
+	return g, nil
+}
+
+// joiner 记录 Join 过程中的修改，失败时按相反顺序撤销
+type joiner struct {
+	orig    string    // 进程原来所在的 cgroup
+	created []string  // 本次创建的目录，由浅到深
+	enabled []control // 本次在 cgroup.subtree_control 中启用的控制器，由浅到深
+	moved   bool
+}
+
+type control struct {
+	dir  string
+	name string
+}
+
+func (j *joiner) join(mount, dir string, setup func(*Group) error) (*Group, error) {
+	if err := j.mkdirAll(mount, dir); err != nil {
+		return nil, err
+	}
+
+	// 自挂载点起逐级启用控制器，直到目标 cgroup 的上级
+	parent := filepath.Dir(dir)
+	for _, d := range ancestors(mount, parent) {
+		err := j.enableControllers(d)
+		if errors.Is(err, syscall.EBUSY) && d == j.orig && !j.moved {
+			// 除根 cgroup 外，有进程的 cgroup 不能为子 cgroup 启用控制器，
+			// 目标位于当前 cgroup 之下时只能先移入进程，再启用控制器和写入上限
+			if err = j.move(dir); err == nil {
+				err = j.enableControllers(d)
+			}
+		}
+		if errors.Is(err, syscall.EBUSY) {
+			return nil, fmt.Errorf("无法在 %s 中启用控制器: 该 cgroup 中还有其他进程，请在独立的 systemd 服务（Delegate=yes）或容器中运行", d)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	g := &Group{Dir: dir}
+	if err := setup(g); err != nil {
+		return nil, err
+	}
+	if !j.moved {
+		if err := j.move(dir); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// mkdirAll 逐级创建 dir，记录新建的目录
+func (j *joiner) mkdirAll(mount, dir string) error {
+	for _, d := range ancestors(mount, dir) {
+		err := os.Mkdir(d, 0755)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("创建 cgroup 失败: %w", err)
+		}
+		j.created = append(j.created, d)
+	}
+	return nil
+}
+
+// enableControllers 在 dir 的 cgroup.subtree_control 中启用所需的控制器
+func (j *joiner) enableControllers(dir string) error {
+	file := filepath.Join(dir, "cgroup.subtree_control")
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("读取 %s 失败: %w", dir, err)
+	}
+
+	enabled := strings.Fields(string(data))
+	for _, name := range controllers {
+		if contains(enabled, name) {
+			continue
+		}
+		if err := os.WriteFile(file, []byte("+"+name), 0644); err != nil {
+			if errors.Is(err, syscall.EBUSY) {
+				return err
+			}
+			return fmt.Errorf("无法在 %s 中启用 %s 控制器: %w", dir, name, err)
+		}
+		j.enabled = append(j.enabled, control{dir: dir, name: name})
+	}
+	return nil
+}
+
+// move 将当前进程移入 dir
+func (j *joiner) move(dir string) error {
+	if err := writeProcs(dir); err != nil {
+		return fmt.Errorf("加入 cgroup %s 失败: %w", dir, err)
+	}
+	j.moved = true
+	return nil
+}
+
+// rollback 关闭本次启用的控制器（原 cgroup 关闭后才能重新容纳进程），移回进程，删除新建的目录
+func (j *joiner) rollback() error {
+	var errs []error
+	for i := len(j.enabled) - 1; i >= 0; i-- {
+		c := j.enabled[i]
+		if err := os.WriteFile(filepath.Join(c.dir, "cgroup.subtree_control"), []byte("-"+c.name), 0644); err != nil {
+			errs = append(errs, fmt.Errorf("关闭 %s 中的 %s 控制器: %w", c.dir, c.name, err))
+		}
+	}
+	if j.moved {
+		if err := writeProcs(j.orig); err != nil {
+			errs = append(errs, fmt.Errorf("移回 %s: %w", j.orig, err))
+		}
+	}
+	for i := len(j.created) - 1; i >= 0; i-- {
+		if err := os.Remove(j.created[i]); err != nil {
+			errs = append(errs, fmt.Errorf("删除 %s: %w", j.created[i], err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func writeProcs(dir string) error {
+	return os.WriteFile(filepath.Join(dir, "cgroup.procs"), []byte(strconv.Itoa(os.Getpid())), 0644)
+}
+
+// ancestors 返回从 mount 起到 dir 为止的各级目录，由浅到深，dir 必须位于 mount 之下
+func ancestors(mount, dir string) []string {
+	var dirs []string
+	for d := dir; d != mount && len(d) > len(mount); d = filepath.Dir(d) {
+		dirs = append(dirs, d)
+	}
+	dirs = append(dirs, mount)
+	for i, k := 0, len(dirs)-1; i < k; i, k = i+1, k-1 {
+		dirs[i], dirs[k] = dirs[k], dirs[i]
+	}
+	return dirs
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// SetCPUMax 设置 cpu.max，cores 为允许使用的核心数，<= 0 时不限制
+func (g *Group) SetCPUMax(cores float64) error {
+	value := fmt.Sprintf("max %d", cpuPeriod)
+	if cores > 0 {
+		// 内核要求 quota 不小于 1ms
+		quota := int64(cores * cpuPeriod)
+		if quota < 1000 {
+			quota = 1000
+		}
+		value = fmt.Sprintf("%d %d", quota, cpuPeriod)
+	}
+	return g.write("cpu.max", value)
+}
+
+// SetMemoryHigh 设置 memory.high，超过后内核回收并限速分配，0 为不限制
+func (g *Group) SetMemoryHigh(bytes uint64) error {
+	return g.write("memory.high", limitValue(bytes))
+}
+
+// SetMemoryMax 设置 memory.max，超过后只在该 cgroup 内触发 OOM，0 为不限制
+func (g *Group) SetMemoryMax(bytes uint64) error {
+	return g.write("memory.max", limitValue(bytes))
+}
+
+func limitValue(bytes uint64) string {
+	if bytes == 0 {
+		return "max"
+	}
+	return strconv.FormatUint(bytes, 10)
+}
+
+func (g *Group) write(name, value string) error {
+	if err := os.WriteFile(filepath.Join(g.Dir, name), []byte(value), 0644); err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", name, err)
+	}
+	return nil
+}
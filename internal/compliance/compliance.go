@@ -0,0 +1,232 @@
+package compliance
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"MikaBooM/internal/controller"
+	"MikaBooM/internal/stats"
+)
+
+// bucketSize 历史样本的聚合粒度，云厂商一般按分钟采样
+const bucketSize = time.Minute
+
+// percentile 闲置判定使用的百分位
+const percentile = 0.95
+
+// Sample 一个时间桶内的平均占用
+type Sample struct {
+	T int64   `json:"t"` // 桶起始时间（Unix 秒）
+	V float64 `json:"v"`
+}
+
+type state struct {
+	Version int                 `json:"version"`
+	Samples map[string][]Sample `json:"samples"`
+}
+
+type bucket struct {
+	start time.Time
+	sum   float64
+	count int
+}
+
+// Tracker 维护滚动窗口内各资源的占用历史，并据此决定是否需要补充负载
+// 只有预计窗口 p95 会低于下限时才启用负载，其余时间保持空闲
+type Tracker struct {
+	mu      sync.Mutex
+	path    string
+	window  time.Duration
+	floors  map[string]int
+	margin  int
+	samples map[string][]Sample
+	current map[string]*bucket
+}
+
+// NewTracker 创建跟踪器并从 path 加载历史，floors 中未配置或为 0 的资源不受影响
+func NewTracker(path string, window time.Duration, floors map[string]int, margin int) (*Tracker, error) {
+	t := &Tracker{
+		path:    path,
+		window:  window,
+		floors:  floors,
+		margin:  margin,
+		samples: make(map[string][]Sample),
+		current: make(map[string]*bucket),
+	}
+
+	if err := t.load(); err != nil {
+		return t, err
+	}
+	return t, nil
+}
+
+// DefaultStatePath 返回配置文件同级目录下的历史文件路径
+func DefaultStatePath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "compliance.json")
+}
+
+// Threshold 实现 controller.Policy
+// 预计 p95 低于下限时返回 下限+余量，使负载把占用推到下限之上；否则返回 0 保持空闲
+func (t *Tracker) Threshold(name string, threshold int, otherUsage float64, now time.Time) int {
+	floor, ok := t.floors[name]
+	if !ok || floor <= 0 {
+		return threshold
+	}
+	// 前序策略（如定时计划）已停止该资源时保持空闲
+	if threshold <= 0 {
+		return 0
+	}
+
+	if t.Projected(name, otherUsage, now) < float64(floor) {
+		return floor + t.margin
+	}
+	return 0
+}
+
+// Observe 实现 controller.Policy，记录系统总占用
+func (t *Tracker) Observe(s controller.ResourceState, now time.Time) {
+	if floor, ok := t.floors[s.Name]; !ok || floor <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	closed := t.record(s.Name, s.Usage, now)
+	t.mu.Unlock()
+
+	if closed {
+		if err := t.Save(); err != nil {
+			log.Printf("%v", err)
+		}
+	}
+}
+
+// record 累加样本，跨入新的时间桶时返回 true
+func (t *Tracker) record(name string, usage float64, now time.Time) bool {
+	start := now.Truncate(bucketSize)
+	b := t.current[name]
+
+	closed := false
+	if b != nil && !b.start.Equal(start) {
+		if b.count > 0 {
+			t.samples[name] = append(t.samples[name], Sample{T: b.start.Unix(), V: b.sum / float64(b.count)})
+			t.prune(name, now)
+			closed = true
+		}
+		b = nil
+	}
+
+	if b == nil {
+		b = &bucket{start: start}
+		t.current[name] = b
+	}
+	b.sum += usage
+	b.count++
+
+	return closed
+}
+
+func (t *Tracker) prune(name string, now time.Time) {
+	cutoff := now.Add(-t.window).Unix()
+	samples := t.samples[name]
+
+	i := 0
+	for i < len(samples) && samples[i].T < cutoff {
+		i++
+	}
+	if i > 0 {
+		t.samples[name] = append([]Sample(nil), samples[i:]...)
+	}
+}
+
+// Projected 预计不补充负载时窗口的 p95
+// 以当前其他程序占用作为下一个时间桶的值，并去掉届时会移出窗口的样本
+func (t *Tracker) Projected(name string, otherUsage float64, now time.Time) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cutoff := now.Add(bucketSize - t.window).Unix()
+	values := []float64{otherUsage}
+	for _, s := range t.samples[name] {
+		if s.T >= cutoff {
+			values = append(values, s.V)
+		}
+	}
+
+	return stats.NearestRank(values, percentile)
+}
+
+// Current 返回窗口内已记录样本的 p95 及样本数
+func (t *Tracker) Current(name string, now time.Time) (float64, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cutoff := now.Add(-t.window).Unix()
+	values := make([]float64, 0, len(t.samples[name]))
+	for _, s := range t.samples[name] {
+		if s.T >= cutoff {
+			values = append(values, s.V)
+		}
+	}
+
+	if len(values) == 0 {
+		return 0, 0
+	}
+	return stats.NearestRank(values, percentile), len(values)
+}
+
+// Floor 返回资源的 p95 下限，未配置时返回 false
+func (t *Tracker) Floor(name string) (int, bool) {
+	floor, ok := t.floors[name]
+	return floor, ok && floor > 0
+}
+
+func (t *Tracker) load() error {
+	data, err := os.ReadFile(t.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取占用历史失败: %w", err)
+	}
+
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fmt.Errorf("解析占用历史失败: %w", err)
+	}
+
+	now := time.Now()
+	for name, samples := range st.Samples {
+		t.samples[name] = samples
+		t.prune(name, now)
+	}
+	return nil
+}
+
+// Save 将历史写入磁盘，先写临时文件再替换，避免中途退出损坏文件
+func (t *Tracker) Save() error {
+	t.mu.Lock()
+	st := state{Version: 1, Samples: make(map[string][]Sample, len(t.samples))}
+	for name, samples := range t.samples {
+		st.Samples[name] = append([]Sample(nil), samples...)
+	}
+	t.mu.Unlock()
+
+	data, err := json.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("序列化占用历史失败: %w", err)
+	}
+
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("写入占用历史失败: %w", err)
+	}
+	if err := os.Rename(tmp, t.path); err != nil {
+		return fmt.Errorf("写入占用历史失败: %w", err)
+	}
+	return nil
+}
@@ -2,21 +2,40 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	CPUThreshold    int                `yaml:"cpu_threshold"`
-	MemoryThreshold int                `yaml:"memory_threshold"`
-	AutoStart       bool               `yaml:"auto_start"`
-	ShowWindow      bool               `yaml:"show_window"`
-	UpdateInterval  int                `yaml:"update_interval"`
-	Notification    NotificationConfig `yaml:"notification"`
-	UpdateCheck     UpdateCheckConfig  `yaml:"update_check"`
-	EnableWorker    bool               `yaml:"-"`
+	CPUThreshold     int                `yaml:"cpu_threshold"`
+	MemoryThreshold  int                `yaml:"memory_threshold"`
+	NetworkThreshold int                `yaml:"network_threshold"`
+	DiskThreshold    int                `yaml:"disk_threshold"`
+	AutoStart        bool               `yaml:"auto_start"`
+	ShowWindow       bool               `yaml:"show_window"`
+	UpdateInterval   int                `yaml:"update_interval"`
+	Notification     NotificationConfig `yaml:"notification"`
+	UpdateCheck      UpdateCheckConfig  `yaml:"update_check"`
+	Control          ControlConfig      `yaml:"control"`
+	CPU              CPUConfig          `yaml:"cpu"`
+	Memory           MemoryConfig       `yaml:"memory"`
+	Priority         PriorityConfig     `yaml:"priority"`
+	Cgroup           CgroupConfig       `yaml:"cgroup"`
+	Pressure         PressureConfig     `yaml:"pressure"`
+	Network          NetworkConfig      `yaml:"network"`
+	Disk             DiskConfig         `yaml:"disk"`
+	Compliance       ComplianceConfig   `yaml:"compliance"`
+	API              APIConfig          `yaml:"api"`
+	Metrics          MetricsConfig      `yaml:"metrics"`
+	History          HistoryConfig      `yaml:"history"`
+	Log              LogConfig          `yaml:"log"`
+	Schedules        []ScheduleConfig   `yaml:"schedules"`
+	EnableWorker     bool               `yaml:"-"`
 }
 
 type NotificationConfig struct {
@@ -31,6 +50,245 @@ type UpdateCheckConfig struct {
 	SilentCheck     bool `yaml:"silent_check"`
 }
 
+const (
+	WorkloadMix      = "mix"
+	WorkloadInteger  = "integer"
+	WorkloadFloat    = "float"
+	WorkloadSHA256   = "sha256"
+	WorkloadCompress = "compress"
+	WorkloadMemory   = "memory"
+	WorkloadVector   = "vector"
+)
+
+// CPUConfig CPU 负载设置
+type CPUConfig struct {
+	PerCore        bool   `yaml:"per_core"`         // 工作线程绑定到各个核心，按该核心的占用分别调节强度（仅 Linux）
+	Workload       string `yaml:"workload"`         // 工作线程执行的计算类型
+	MemoryKernelMB int    `yaml:"memory_kernel_mb"` // memory 计算类型每个工作线程使用的内存（MB）
+}
+
+const (
+	AllocatorHeap = "heap"
+	AllocatorMmap = "mmap"
+)
+
+const (
+	FillRandom = "random"
+	FillSparse = "sparse"
+)
+
+// MemoryConfig 内存负载设置
+type MemoryConfig struct {
+	Allocator     string `yaml:"allocator"`      // heap: Go 堆, mmap: 匿名映射，释放时立即归还系统
+	Fill          string `yaml:"fill"`           // random: 各内存块写满不同的伪随机数据, sparse: 每个页面写入一个字节
+	TouchInterval int    `yaml:"touch_interval"` // 定期改写所有页面的间隔（秒），0 为不改写
+	Mlock         bool   `yaml:"mlock"`          // 锁定已分配的内存，禁止换出
+	OOMScoreAdj   int    `yaml:"oom_score_adj"`  // 启动时写入的 oom_score_adj，0 为不修改（仅 Linux）
+	MinAvailable  int    `yaml:"min_available"`  // 系统可用内存下限 (MB)，低于时立即释放全部内存，0 为不检查
+}
+
+const (
+	PriorityNormal = "normal"
+	PriorityNice   = "nice"
+	PriorityIdle   = "idle"
+)
+
+// PriorityConfig 工作线程调度优先级设置（仅 Linux）
+type PriorityConfig struct {
+	CPU    string `yaml:"cpu"`     // normal: 普通优先级, nice: nice 19, idle: SCHED_IDLE
+	IOIdle bool   `yaml:"io_idle"` // 工作线程的 I/O 优先级设为 idle
+}
+
+const (
+	CgroupModeAuto   = "auto"
+	CgroupModeHost   = "host"
+	CgroupModeCgroup = "cgroup"
+)
+
+// CgroupConfig 容器资源限制识别（cgroup v2，仅 Linux）
+type CgroupConfig struct {
+	Mode    string `yaml:"mode"`    // auto: 设置了上限时按 cgroup 计算, host: 始终按整机计算, cgroup: 始终按 cgroup 计算
+	Confine bool   `yaml:"confine"` // 在专用的子 cgroup 中运行，按阈值设置 cpu.max、memory.high 和 memory.max
+	Path    string `yaml:"path"`    // 专用 cgroup 路径，相对路径位于当前 cgroup 之下，/ 开头时相对于挂载点
+}
+
+// PressureConfig 基于 PSI（/proc/pressure）的紧急退让设置（仅 Linux），数值为最近 10 秒的停顿百分比，0 为不检查
+type PressureConfig struct {
+	MemorySome float64 `yaml:"memory_some"` // 内存 some 停顿超过时立即释放内存
+	MemoryFull float64 `yaml:"memory_full"` // 内存 full 停顿超过时立即释放内存
+	CPUSome    float64 `yaml:"cpu_some"`    // CPU some 停顿超过时立即停止加负载
+}
+
+// NetworkConfig 网络负载设置
+type NetworkConfig struct {
+	Endpoint   string `yaml:"endpoint"`    // 流量目标地址 host:port (TCP)
+	Sink       bool   `yaml:"sink"`        // 是否启动本地接收端
+	SinkListen string `yaml:"sink_listen"` // 本地接收端监听地址
+	Interface  string `yaml:"interface"`   // 统计带宽的网卡，空为除回环网卡外的全部
+	MaxMbps    int    `yaml:"max_mbps"`    // 网络工作器发送带宽上限
+}
+
+// DiskConfig 磁盘负载设置
+type DiskConfig struct {
+	Directory  string   `yaml:"directory"`    // 临时文件所在目录，空为系统临时目录
+	FileSizeMB int      `yaml:"file_size_mb"` // 临时文件大小上限
+	DirectIO   bool     `yaml:"direct_io"`    // 是否使用 O_DIRECT 绕过页缓存
+	Devices    []string `yaml:"devices"`      // 统计吞吐量的设备，空为全部
+	MaxMBps    int      `yaml:"max_mbps"`     // 磁盘工作器吞吐量上限
+}
+
+const (
+	ComplianceModeThreshold = "threshold"
+	ComplianceModeP95       = "p95"
+)
+
+// ComplianceConfig 闲置回收合规模式设置
+type ComplianceConfig struct {
+	Mode         string `yaml:"mode"`          // threshold: 始终维持阈值, p95: 只保证滚动窗口 p95 不低于下限
+	WindowHours  int    `yaml:"window_hours"`  // 滚动窗口长度（小时）
+	CPUFloor     int    `yaml:"cpu_floor"`     // CPU p95 下限 (%)，0 为不参与
+	MemoryFloor  int    `yaml:"memory_floor"`  // 内存 p95 下限 (%)，0 为不参与
+	NetworkFloor int    `yaml:"network_floor"` // 网络 p95 下限 (Mbps)，0 为不参与
+	Margin       int    `yaml:"margin"`        // 补充负载时高出下限的余量
+	StateFile    string `yaml:"state_file"`    // 历史文件路径，空为配置文件同级目录下的 compliance.json
+}
+
+// APIUnixPrefix 控制接口监听 Unix socket 时地址的前缀
+const APIUnixPrefix = "unix:"
+
+// APIConfig 本地控制接口设置
+type APIConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Listen  string `yaml:"listen"` // 127.0.0.1:port 或 unix:/path/to/socket
+	Token   string `yaml:"token"`  // 请求需携带 Authorization: Bearer <token>
+}
+
+// MetricsConfig Prometheus 指标设置
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Listen  string `yaml:"listen"` // 监听地址 host:port
+	Path    string `yaml:"path"`   // 指标路径
+}
+
+// HistoryConfig 占用历史记录设置
+type HistoryConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Path      string `yaml:"path"`        // 历史文件路径，空为配置文件同级目录下的 history/history.jsonl
+	Interval  int    `yaml:"interval"`    // 汇总写入间隔（秒）
+	MaxSizeMB int    `yaml:"max_size_mb"` // 单个文件大小上限，超过后轮转
+	MaxFiles  int    `yaml:"max_files"`   // 保留的轮转文件数
+}
+
+// LogConfig 日志文件设置
+type LogConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	Level      string `yaml:"level"`        // debug / info / warn / error
+	Format     string `yaml:"format"`       // text / json
+	Path       string `yaml:"path"`         // 日志文件路径，空为配置文件同级目录下的 logs/mikaboom.log
+	MaxSizeMB  int    `yaml:"max_size_mb"`  // 单个文件大小上限，超过后轮转
+	MaxFiles   int    `yaml:"max_files"`    // 保留的轮转文件数
+	MaxAgeDays int    `yaml:"max_age_days"` // 轮转文件保留天数，0 为不按时间删除
+}
+
+// ScheduleConfig 按时间段覆盖阈值的计划规则，按顺序匹配，第一条命中的规则生效
+type ScheduleConfig struct {
+	Name    string   `yaml:"name"`
+	Days    []string `yaml:"days,omitempty"`  // mon..sun / weekdays / weekends / daily，空为每天
+	Start   string   `yaml:"start,omitempty"` // HH:MM，空为 00:00
+	End     string   `yaml:"end,omitempty"`   // HH:MM，早于 start 表示跨越午夜，与 start 相同表示全天
+	Off     bool     `yaml:"off,omitempty"`   // 停止所有负载
+	CPU     *int     `yaml:"cpu,omitempty"`
+	Memory  *int     `yaml:"memory,omitempty"`
+	Network *int     `yaml:"network,omitempty"`
+	Disk    *int     `yaml:"disk,omitempty"`
+}
+
+var weekdayNames = map[string][]time.Weekday{
+	"sun":      {time.Sunday},
+	"mon":      {time.Monday},
+	"tue":      {time.Tuesday},
+	"wed":      {time.Wednesday},
+	"thu":      {time.Thursday},
+	"fri":      {time.Friday},
+	"sat":      {time.Saturday},
+	"weekdays": {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekends": {time.Saturday, time.Sunday},
+	"daily":    {time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday},
+}
+
+// Weekdays 解析规则生效的星期，未配置时返回 nil 表示每天
+func (s ScheduleConfig) Weekdays() (map[time.Weekday]bool, error) {
+	if len(s.Days) == 0 {
+		return nil, nil
+	}
+
+	days := make(map[time.Weekday]bool)
+	for _, name := range s.Days {
+		list, ok := weekdayNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("未知的星期: %s (可选 mon..sun、weekdays、weekends、daily)", name)
+		}
+		for _, d := range list {
+			days[d] = true
+		}
+	}
+	return days, nil
+}
+
+// Window 解析规则的起止时间，返回自零点起的分钟数
+func (s ScheduleConfig) Window() (start, end int, err error) {
+	if start, err = parseClock(s.Start); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parseClock(s.End); err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// Thresholds 返回规则覆盖的阈值，键为资源名称
+func (s ScheduleConfig) Thresholds() map[string]int {
+	thresholds := make(map[string]int)
+	if s.CPU != nil {
+		thresholds["cpu"] = *s.CPU
+	}
+	if s.Memory != nil {
+		thresholds["memory"] = *s.Memory
+	}
+	if s.Network != nil {
+		thresholds["network"] = *s.Network
+	}
+	if s.Disk != nil {
+		thresholds["disk"] = *s.Disk
+	}
+	return thresholds
+}
+
+func parseClock(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("无效的时间: %s (格式 HH:MM)", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ControlConfig 负载调节控制器参数
+type ControlConfig struct {
+	CPU    PIDConfig `yaml:"cpu"`
+	Memory PIDConfig `yaml:"memory"`
+}
+
+// PIDConfig PID 控制器增益，ki/kd 以秒为时间单位
+type PIDConfig struct {
+	Kp float64 `yaml:"kp"`
+	Ki float64 `yaml:"ki"`
+	Kd float64 `yaml:"kd"`
+}
+
 func GetDefaultConfig() *Config {
 	return &Config{
 		CPUThreshold:    70,
@@ -48,6 +306,86 @@ func GetDefaultConfig() *Config {
 			AutoDownload:   false,
 			SilentCheck:    false,
 		},
+		Control: ControlConfig{
+			CPU:    PIDConfig{Kp: 0.2, Ki: 0.2, Kd: 0},
+			Memory: PIDConfig{Kp: 0.2, Ki: 0.2, Kd: 0},
+		},
+		CPU: CPUConfig{
+			PerCore:        false,
+			Workload:       WorkloadMix,
+			MemoryKernelMB: 24,
+		},
+		Memory: MemoryConfig{
+			Allocator:     AllocatorHeap,
+			Fill:          FillSparse,
+			TouchInterval: 0,
+			Mlock:         false,
+			OOMScoreAdj:   0,
+			MinAvailable:  0,
+		},
+		Priority: PriorityConfig{
+			CPU:    PriorityNormal,
+			IOIdle: false,
+		},
+		Pressure: PressureConfig{
+			MemorySome: 0,
+			MemoryFull: 0,
+			CPUSome:    0,
+		},
+		Cgroup: CgroupConfig{
+			Mode:    CgroupModeAuto,
+			Confine: false,
+			Path:    "mikaboom",
+		},
+		Network: NetworkConfig{
+			Endpoint:   "",
+			Sink:       false,
+			SinkListen: "127.0.0.1:9531",
+			Interface:  "",
+			MaxMbps:    100,
+		},
+		Disk: DiskConfig{
+			Directory:  "",
+			FileSizeMB: 256,
+			DirectIO:   true,
+			Devices:    []string{},
+			MaxMBps:    100,
+		},
+		Compliance: ComplianceConfig{
+			Mode:         ComplianceModeThreshold,
+			WindowHours:  168,
+			CPUFloor:     20,
+			MemoryFloor:  20,
+			NetworkFloor: 0,
+			Margin:       5,
+			StateFile:    "",
+		},
+		API: APIConfig{
+			Enabled: false,
+			Listen:  "127.0.0.1:9530",
+			Token:   "",
+		},
+		Metrics: MetricsConfig{
+			Enabled: false,
+			Listen:  "127.0.0.1:9532",
+			Path:    "/metrics",
+		},
+		History: HistoryConfig{
+			Enabled:   false,
+			Path:      "",
+			Interval:  60,
+			MaxSizeMB: 10,
+			MaxFiles:  10,
+		},
+		Log: LogConfig{
+			Enabled:    true,
+			Level:      "info",
+			Format:     "text",
+			Path:       "",
+			MaxSizeMB:  10,
+			MaxFiles:   5,
+			MaxAgeDays: 30,
+		},
 		EnableWorker: true,
 	}
 }
@@ -104,6 +442,16 @@ cpu_threshold: %d
 # 当其他程序内存占用低于此阈值时，本程序会启动内存计算负载
 memory_threshold: %d
 
+# 网络发送带宽阈值 (Mbps)
+# 当其他程序的发送带宽低于此阈值时，本程序会向 network.endpoint 发送流量补足
+# 0 表示禁用网络负载
+network_threshold: %d
+
+# 磁盘读写吞吐量阈值 (MB/s)
+# 当其他程序的磁盘吞吐量低于此阈值时，本程序会读写临时文件补足
+# 0 表示禁用磁盘负载
+disk_threshold: %d
+
 # 是否开机自启动
 # true: 启用自启动, false: 禁用自启动
 auto_start: %t
@@ -134,9 +482,209 @@ update_check:
   auto_download: %t
   # 是否静默检查（不显示"已是最新版本"的提示）
   silent_check: %t
-`,
+
+# 负载调节控制器（PID）参数
+# kp: 比例增益, ki: 积分增益（每秒）, kd: 微分增益（秒）
+# 增益越大收敛越快，但过大会导致在阈值附近振荡
+control:
+  cpu:
+    kp: %g
+    ki: %g
+    kd: %g
+  memory:
+    kp: %g
+    ki: %g
+    kd: %g
+
+# CPU 负载设置
+cpu:
+  # 每个工作线程绑定到一个核心，按该核心的占用分别调节强度
+  # 已被其他程序占满的核心不再加负载（仅 Linux，其他平台按整机平均占用调节）
+  # 默认关闭，按整机平均占用调节；启用示例: per_core: true
+  per_core: %t
+  # 工作线程执行的计算类型，可以按监控系统期望的负载特征选择
+  # mix: 圆周率、三角函数、矩阵、对数等混合运算
+  # integer: 整数运算和位运算
+  # float: 双精度浮点乘加、除法和开方
+  # sha256: SHA-256 哈希
+  # compress: compress/flate 压缩
+  # memory: 顺序读写大数组，主要占用内存带宽（每个工作线程额外占用 memory_kernel_mb 的内存）
+  # vector: 单精度向量运算，CPU 支持时使用 AVX 指令（仅 amd64，其他情况为等价的标量运算）
+  workload: %q
+  # workload 为 memory 时每个工作线程读写的数组大小 (MB)，应大于 CPU 的末级缓存
+  # 这部分内存按其他程序的占用计算，会相应减少内存工作器分配的内存
+  memory_kernel_mb: %d
+
+# 内存负载设置
+memory:
+  # 内存块的分配方式
+  # mmap: 使用匿名映射 (Windows 为 VirtualAlloc)，释放时立即归还系统，占用与报告的大小一致
+  # heap: 使用 Go 堆，释放后由垃圾回收决定何时归还系统，可能在数分钟内仍然驻留
+  # 默认 heap；需要释放后立即归还系统时: allocator: "mmap"
+  allocator: %q
+  # 内存块写入的内容
+  # random: 每个内存块写满不同的伪随机数据，无法被 KSM 合并或被 zram 压缩
+  # sparse: 每个页面只写入一个字节，内容在各内存块之间相同，可能被合并或压缩而不再计入占用
+  # 默认 sparse；启用了 KSM 或 zram 的主机上: fill: "random"
+  fill: %q
+  # 定期改写所有页面的间隔（秒），使长时间未访问的页面不被换出或压缩，0 为不改写
+  # 默认不改写，例如每分钟改写一次: touch_interval: 60
+  touch_interval: %d
+  # 锁定已分配的内存，禁止换出（需要足够的 RLIMIT_MEMLOCK 或 CAP_IPC_LOCK 权限）
+  mlock: %t
+  # 启动时写入的 oom_score_adj (-1000 到 1000)，发生 OOM 时优先终止本程序而不是其他服务
+  # 0 为不修改，低于 0 需要 CAP_SYS_RESOURCE 权限（仅 Linux）
+  # 默认不修改，OOM 时优先终止本程序: oom_score_adj: 1000
+  oom_score_adj: %d
+  # 系统可用内存 (MemAvailable) 下限 (MB)，低于时不论阈值如何立即释放全部内存，0 为不检查
+  # 默认不检查，例如保留 256MB: min_available: 256
+  min_available: %d
+
+# 工作线程调度优先级（仅 Linux）
+# 降低优先级后真实负载出现时会立即抢占工作线程，可以适当提高阈值
+priority:
+  # normal: 普通优先级
+  # nice: nice 19，与其他程序竞争时只分到很少的 CPU 时间
+  # idle: SCHED_IDLE，只在 CPU 空闲时运行
+  cpu: %q
+  # 将工作线程的 I/O 优先级设为 idle，磁盘负载只使用空闲的磁盘带宽
+  io_idle: %t
+
+# 容器资源限制（cgroup v2，仅 Linux）
+# Docker、Kubernetes 等容器内按容器的 CPU 和内存上限（cpu.max、memory.max）计算占用
+cgroup:
+  # auto: 检测到 cgroup 设置了上限时按 cgroup 计算，否则按整机计算
+  # host: 始终按整机计算
+  # cgroup: 始终按当前 cgroup 计算，未设置上限时以整机容量为上限
+  mode: %q
+  # 在专用的子 cgroup 中运行（整个进程移入），由内核按阈值强制限制工作器的占用上限
+  # cpu.max 为 CPU 阈值，memory.high 为内存阈值，memory.max 比内存阈值高 5%%
+  # 超过 memory.max 时只会在该 cgroup 内触发 OOM，不会影响其他服务
+  # 要求上级 cgroup 中没有其他进程，例如 Delegate=yes 的 systemd 服务或容器
+  confine: %t
+  # 专用 cgroup 路径，相对路径位于当前 cgroup 之下，以 / 开头时相对于 cgroup v2 挂载点
+  path: %q
+
+# 资源压力紧急退让（PSI，需要 Linux 4.20 以上）
+# 每 0.5 秒读取 /proc/pressure，数值为最近 10 秒内任务因资源不足而停顿的时间百分比，0 为不检查
+# 超过上限时不等待下一次调节，立即释放一半已分配的内存或将 CPU 强度降为 0，并在 30 秒内不再增加
+# 释放内存后 10 秒内（数值的统计窗口）不再因压力继续释放
+# 默认不检查，启用示例: memory_some: 10, memory_full: 2
+pressure:
+  # 至少有一个任务在等待内存
+  memory_some: %g
+  # 所有任务同时在等待内存
+  memory_full: %g
+  # 至少有一个任务在等待 CPU；CPU 工作器本身也会产生 CPU 压力，启用时请设置较高的值
+  cpu_some: %g
+
+# 网络负载设置（network_threshold 大于 0 时生效）
+network:
+  # 流量目标地址 (host:port, TCP)，留空且启用 sink 时发送到本地接收端
+  endpoint: %q
+  # 是否启动本地接收端（丢弃收到的数据），可用于测试；流量只经过回环网卡，需将 interface 设为回环网卡才会计入
+  sink: %t
+  # 本地接收端监听地址
+  sink_listen: %q
+  # 统计带宽的网卡名称，留空统计除回环网卡外的所有网卡
+  interface: %q
+  # 网络工作器发送带宽上限 (Mbps)
+  max_mbps: %d
+
+# 磁盘负载设置（disk_threshold 大于 0 时生效）
+disk:
+  # 临时文件所在目录，留空使用系统临时目录
+  directory: %q
+  # 临时文件大小上限 (MB)，停止时自动删除
+  file_size_mb: %d
+  # 是否使用 O_DIRECT 绕过页缓存（仅 Linux，不支持时自动回退）
+  direct_io: %t
+  # 统计吞吐量的设备名称，例如 ["sda", "nvme0n1"]，留空统计所有整盘设备（不含分区、loop、LVM 等）
+  devices: [%s]
+  # 磁盘工作器吞吐量上限 (MB/s)
+  max_mbps: %d
+
+# 闲置回收合规模式
+# 云厂商（如 Oracle Always Free）按 7 天内占用的 95 百分位判定实例是否闲置
+compliance:
+  # threshold: 始终将占用维持在各项阈值
+  # p95: 记录滚动窗口历史，只在预计 p95 会低于下限时补充负载，其余时间保持空闲
+  mode: %q
+  # 滚动窗口长度（小时），7 天为 168
+  window_hours: %d
+  # 各资源的 p95 下限，0 表示该资源不参与（仍按普通阈值工作）
+  cpu_floor: %d
+  memory_floor: %d
+  # 网络下限单位为 Mbps，需要同时配置 network.endpoint 或 network.sink
+  network_floor: %d
+  # 补充负载时高出下限的余量，避免控制误差导致样本略低于下限
+  margin: %d
+  # 历史文件路径，留空保存在配置文件同级目录下的 compliance.json
+  state_file: %q
+
+# 本地控制接口，用于查询状态、暂停/恢复工作器、运行时调整阈值和退出
+api:
+  enabled: %t
+  # 只允许本机地址，或使用 Unix socket，如 unix:/run/mikaboom.sock
+  listen: %q
+  # 访问令牌，启用时必须设置
+  token: %q
+
+# Prometheus 指标
+metrics:
+  enabled: %t
+  # 需要从其他主机抓取时可改为 0.0.0.0:9532
+  listen: %q
+  path: %q
+
+# 占用历史记录，可用 MikaBooM report --since 24h 查看统计
+history:
+  enabled: %t
+  # 历史文件路径，留空保存在配置文件同级目录下的 history/history.jsonl
+  path: %q
+  # 汇总写入间隔（秒），每条记录同时保存周期内的每次采样，报告的百分位和最大值基于采样计算
+  interval: %d
+  # 单个文件大小上限 (MB) 和保留的轮转文件数
+  max_size_mb: %d
+  max_files: %d
+
+# 日志文件，后台运行时可通过日志排查问题
+log:
+  enabled: %t
+  # debug / info / warn / error
+  level: %q
+  # text / json
+  format: %q
+  # 日志文件路径，留空保存在配置文件同级目录下的 logs/mikaboom.log
+  path: %q
+  # 单个文件大小上限 (MB)、保留的轮转文件数和保留天数
+  max_size_mb: %d
+  max_files: %d
+  max_age_days: %d
+
+# 定时计划：按时间段覆盖阈值，按顺序匹配，第一条命中的规则生效
+# days: mon..sun / weekdays / weekends / daily，留空为每天
+# start/end: HH:MM，end 早于 start 表示跨越午夜（如 18:00-09:00）
+# off: true 停止所有负载；cpu/memory/network/disk 覆盖对应阈值，未填写的沿用全局阈值
+# 示例:
+# schedules:
+#   - name: 工作时间
+#     days: [weekdays]
+#     start: "09:00"
+#     end: "18:00"
+#     cpu: 20
+#   - name: 夜间
+#     start: "18:00"
+#     end: "09:00"
+#     cpu: 60
+#   - name: 周末
+#     days: [weekends]
+#     off: true
+%s`,
 		cfg.CPUThreshold,
 		cfg.MemoryThreshold,
+		cfg.NetworkThreshold,
+		cfg.DiskThreshold,
 		cfg.AutoStart,
 		cfg.ShowWindow,
 		cfg.UpdateInterval,
@@ -146,9 +694,95 @@ update_check:
 		cfg.UpdateCheck.CheckOnStartup,
 		cfg.UpdateCheck.AutoDownload,
 		cfg.UpdateCheck.SilentCheck,
+		cfg.Control.CPU.Kp,
+		cfg.Control.CPU.Ki,
+		cfg.Control.CPU.Kd,
+		cfg.Control.Memory.Kp,
+		cfg.Control.Memory.Ki,
+		cfg.Control.Memory.Kd,
+		cfg.CPU.PerCore,
+		cfg.CPU.Workload,
+		cfg.CPU.MemoryKernelMB,
+		cfg.Memory.Allocator,
+		cfg.Memory.Fill,
+		cfg.Memory.TouchInterval,
+		cfg.Memory.Mlock,
+		cfg.Memory.OOMScoreAdj,
+		cfg.Memory.MinAvailable,
+		cfg.Priority.CPU,
+		cfg.Priority.IOIdle,
+		cfg.Cgroup.Mode,
+		cfg.Cgroup.Confine,
+		cfg.Cgroup.Path,
+		cfg.Pressure.MemorySome,
+		cfg.Pressure.MemoryFull,
+		cfg.Pressure.CPUSome,
+		cfg.Network.Endpoint,
+		cfg.Network.Sink,
+		cfg.Network.SinkListen,
+		cfg.Network.Interface,
+		cfg.Network.MaxMbps,
+		cfg.Disk.Directory,
+		cfg.Disk.FileSizeMB,
+		cfg.Disk.DirectIO,
+		yamlStringList(cfg.Disk.Devices),
+		cfg.Disk.MaxMBps,
+		cfg.Compliance.Mode,
+		cfg.Compliance.WindowHours,
+		cfg.Compliance.CPUFloor,
+		cfg.Compliance.MemoryFloor,
+		cfg.Compliance.NetworkFloor,
+		cfg.Compliance.Margin,
+		cfg.Compliance.StateFile,
+		cfg.API.Enabled,
+		cfg.API.Listen,
+		cfg.API.Token,
+		cfg.Metrics.Enabled,
+		cfg.Metrics.Listen,
+		cfg.Metrics.Path,
+		cfg.History.Enabled,
+		cfg.History.Path,
+		cfg.History.Interval,
+		cfg.History.MaxSizeMB,
+		cfg.History.MaxFiles,
+		cfg.Log.Enabled,
+		cfg.Log.Level,
+		cfg.Log.Format,
+		cfg.Log.Path,
+		cfg.Log.MaxSizeMB,
+		cfg.Log.MaxFiles,
+		cfg.Log.MaxAgeDays,
+		yamlSchedules(cfg.Schedules),
 	)
 }
 
+// yamlSchedules 将计划规则格式化为 YAML
+func yamlSchedules(schedules []ScheduleConfig) string {
+	if len(schedules) == 0 {
+		return "schedules: []\n"
+	}
+
+	var buf strings.Builder
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(struct {
+		Schedules []ScheduleConfig `yaml:"schedules"`
+	}{schedules}); err != nil {
+		return "schedules: []\n"
+	}
+	enc.Close()
+	return buf.String()
+}
+
+// yamlStringList 将字符串列表格式化为 YAML 行内列表的内容
+func yamlStringList(items []string) string {
+	quoted := make([]string, 0, len(items))
+	for _, item := range items {
+		quoted = append(quoted, fmt.Sprintf("%q", item))
+	}
+	return strings.Join(quoted, ", ")
+}
+
 func FindConfigFile(specifiedPath string) (string, error) {
 	if specifiedPath != "" {
 		if filepath.IsAbs(specifiedPath) {
@@ -191,6 +825,201 @@ func ValidateConfig(cfg *Config) error {
 		return fmt.Errorf("内存阈值必须在 0-100 之间，当前值: %d", cfg.MemoryThreshold)
 	}
 
+	if cfg.NetworkThreshold < 0 {
+		return fmt.Errorf("网络带宽阈值不能为负数，当前值: %d", cfg.NetworkThreshold)
+	}
+
+	if cfg.NetworkEnabled() {
+		if cfg.Network.Endpoint == "" && !cfg.Network.Sink {
+			return fmt.Errorf("启用网络负载时必须设置 network.endpoint 或启用 network.sink")
+		}
+		if cfg.Network.Sink && cfg.Network.SinkListen == "" {
+			return fmt.Errorf("启用本地接收端时必须设置 network.sink_listen")
+		}
+		if cfg.Network.MaxMbps < 0 {
+			return fmt.Errorf("网络带宽上限不能为负数，当前值: %d", cfg.Network.MaxMbps)
+		}
+		if cfg.Network.Interface != "" {
+			if _, err := net.InterfaceByName(cfg.Network.Interface); err != nil {
+				return fmt.Errorf("未找到 network.interface 指定的网卡 %s: %w", cfg.Network.Interface, err)
+			}
+		}
+	}
+
+	if cfg.DiskThreshold < 0 {
+		return fmt.Errorf("磁盘吞吐量阈值不能为负数，当前值: %d", cfg.DiskThreshold)
+	}
+
+	if cfg.DiskEnabled() {
+		if cfg.Disk.FileSizeMB < 1 {
+			return fmt.Errorf("磁盘临时文件大小必须大于 0，当前值: %d", cfg.Disk.FileSizeMB)
+		}
+		if cfg.Disk.MaxMBps < 0 {
+			return fmt.Errorf("磁盘吞吐量上限不能为负数，当前值: %d", cfg.Disk.MaxMBps)
+		}
+	}
+
+	switch cfg.Memory.Allocator {
+	case AllocatorHeap, AllocatorMmap, "":
+	default:
+		return fmt.Errorf("未知的内存分配方式: %s (可选 mmap 或 heap)", cfg.Memory.Allocator)
+	}
+
+	switch cfg.Memory.Fill {
+	case FillRandom, FillSparse, "":
+	default:
+		return fmt.Errorf("未知的内存写入方式: %s (可选 random 或 sparse)", cfg.Memory.Fill)
+	}
+
+	if cfg.Memory.TouchInterval < 0 {
+		return fmt.Errorf("页面改写间隔不能为负数，当前值: %d", cfg.Memory.TouchInterval)
+	}
+
+	if cfg.Memory.OOMScoreAdj < -1000 || cfg.Memory.OOMScoreAdj > 1000 {
+		return fmt.Errorf("oom_score_adj 必须在 -1000-1000 之间，当前值: %d", cfg.Memory.OOMScoreAdj)
+	}
+
+	if cfg.Memory.MinAvailable < 0 {
+		return fmt.Errorf("可用内存下限不能为负数，当前值: %d", cfg.Memory.MinAvailable)
+	}
+
+	switch cfg.CPU.Workload {
+	case WorkloadMix, WorkloadInteger, WorkloadFloat, WorkloadSHA256, WorkloadCompress, WorkloadMemory, WorkloadVector, "":
+	default:
+		return fmt.Errorf("未知的 CPU 计算类型: %s (可选 mix、integer、float、sha256、compress、memory 或 vector)", cfg.CPU.Workload)
+	}
+	if cfg.CPU.Workload == WorkloadMemory && cfg.CPU.MemoryKernelMB < 1 {
+		return fmt.Errorf("memory 计算类型使用的内存必须大于 0，当前值: %d", cfg.CPU.MemoryKernelMB)
+	}
+
+	switch cfg.Priority.CPU {
+	case PriorityNormal, PriorityNice, PriorityIdle, "":
+	default:
+		return fmt.Errorf("未知的工作线程优先级: %s (可选 normal、nice 或 idle)", cfg.Priority.CPU)
+	}
+
+	switch cfg.Cgroup.Mode {
+	case CgroupModeAuto, CgroupModeHost, CgroupModeCgroup, "":
+	default:
+		return fmt.Errorf("未知的 cgroup 模式: %s (可选 auto、host 或 cgroup)", cfg.Cgroup.Mode)
+	}
+
+	if cfg.Cgroup.Confine {
+		if cfg.Cgroup.Path == "" || cfg.Cgroup.Path == "/" {
+			return fmt.Errorf("启用专用 cgroup 时必须设置 cgroup.path")
+		}
+		for _, part := range strings.Split(cfg.Cgroup.Path, "/") {
+			if part == ".." {
+				return fmt.Errorf("cgroup.path 不能包含 ..: %s", cfg.Cgroup.Path)
+			}
+		}
+	}
+
+	for _, limit := range []struct {
+		name  string
+		value float64
+	}{
+		{"memory_some", cfg.Pressure.MemorySome},
+		{"memory_full", cfg.Pressure.MemoryFull},
+		{"cpu_some", cfg.Pressure.CPUSome},
+	} {
+		if limit.value < 0 || limit.value > 100 {
+			return fmt.Errorf("pressure.%s 必须在 0-100 之间，当前值: %g", limit.name, limit.value)
+		}
+	}
+
+	switch cfg.Compliance.Mode {
+	case ComplianceModeThreshold, "":
+	case ComplianceModeP95:
+		if cfg.Compliance.WindowHours < 1 {
+			return fmt.Errorf("合规窗口长度必须大于 0，当前值: %d", cfg.Compliance.WindowHours)
+		}
+		if cfg.Compliance.CPUFloor < 0 || cfg.Compliance.CPUFloor > 100 {
+			return fmt.Errorf("CPU p95 下限必须在 0-100 之间，当前值: %d", cfg.Compliance.CPUFloor)
+		}
+		if cfg.Compliance.MemoryFloor < 0 || cfg.Compliance.MemoryFloor > 100 {
+			return fmt.Errorf("内存 p95 下限必须在 0-100 之间，当前值: %d", cfg.Compliance.MemoryFloor)
+		}
+		if cfg.Compliance.NetworkFloor < 0 {
+			return fmt.Errorf("网络 p95 下限不能为负数，当前值: %d", cfg.Compliance.NetworkFloor)
+		}
+		if cfg.Compliance.Margin < 0 {
+			return fmt.Errorf("合规余量不能为负数，当前值: %d", cfg.Compliance.Margin)
+		}
+	default:
+		return fmt.Errorf("未知的合规模式: %s (可选 threshold 或 p95)", cfg.Compliance.Mode)
+	}
+
+	if cfg.API.Enabled {
+		if cfg.API.Token == "" {
+			return fmt.Errorf("启用控制接口时必须设置 api.token")
+		}
+		if err := validateAPIListen(cfg.API.Listen); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Metrics.Enabled {
+		if _, _, err := net.SplitHostPort(cfg.Metrics.Listen); err != nil {
+			return fmt.Errorf("无效的指标服务地址: %s", cfg.Metrics.Listen)
+		}
+		if !strings.HasPrefix(cfg.Metrics.Path, "/") {
+			return fmt.Errorf("指标路径必须以 / 开头，当前值: %s", cfg.Metrics.Path)
+		}
+	}
+
+	if cfg.History.Enabled {
+		if cfg.History.Interval < 1 {
+			return fmt.Errorf("历史记录间隔必须大于 0，当前值: %d", cfg.History.Interval)
+		}
+		if cfg.History.MaxSizeMB < 1 {
+			return fmt.Errorf("历史文件大小上限必须大于 0，当前值: %d", cfg.History.MaxSizeMB)
+		}
+		if cfg.History.MaxFiles < 0 {
+			return fmt.Errorf("历史轮转文件数不能为负数，当前值: %d", cfg.History.MaxFiles)
+		}
+	}
+
+	switch strings.ToLower(cfg.Log.Level) {
+	case "debug", "info", "warn", "warning", "error", "":
+	default:
+		return fmt.Errorf("未知的日志级别: %s (可选 debug、info、warn、error)", cfg.Log.Level)
+	}
+	switch strings.ToLower(cfg.Log.Format) {
+	case "text", "json", "":
+	default:
+		return fmt.Errorf("未知的日志格式: %s (可选 text 或 json)", cfg.Log.Format)
+	}
+	if cfg.Log.Enabled {
+		if cfg.Log.MaxSizeMB < 1 {
+			return fmt.Errorf("日志文件大小上限必须大于 0，当前值: %d", cfg.Log.MaxSizeMB)
+		}
+		if cfg.Log.MaxFiles < 0 || cfg.Log.MaxAgeDays < 0 {
+			return fmt.Errorf("日志轮转文件数和保留天数不能为负数")
+		}
+	}
+
+	for i, rule := range cfg.Schedules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if _, err := rule.Weekdays(); err != nil {
+			return fmt.Errorf("计划 %s: %w", name, err)
+		}
+		if _, _, err := rule.Window(); err != nil {
+			return fmt.Errorf("计划 %s: %w", name, err)
+		}
+		for res, value := range rule.Thresholds() {
+			if value < 0 {
+				return fmt.Errorf("计划 %s: %s 阈值不能为负数，当前值: %d", name, res, value)
+			}
+			if (res == "cpu" || res == "memory") && value > 100 {
+				return fmt.Errorf("计划 %s: %s 阈值必须在 0-100 之间，当前值: %d", name, res, value)
+			}
+		}
+	}
+
 	if cfg.UpdateInterval < 1 {
 		return fmt.Errorf("更新间隔必须大于 0，当前值: %d", cfg.UpdateInterval)
 	}
@@ -199,11 +1028,94 @@ func ValidateConfig(cfg *Config) error {
 		return fmt.Errorf("通知冷却时间不能为负数，当前值: %d", cfg.Notification.Cooldown)
 	}
 
+	if err := validatePID("CPU", cfg.Control.CPU); err != nil {
+		return err
+	}
+
+	if err := validatePID("内存", cfg.Control.Memory); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// DiskEnabled 是否需要启用磁盘负载
+func (cfg *Config) DiskEnabled() bool {
+	if cfg.DiskThreshold > 0 {
+		return true
+	}
+	for _, rule := range cfg.Schedules {
+		if rule.Disk != nil && *rule.Disk > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// NetworkEnabled 是否需要启用网络负载
+func (cfg *Config) NetworkEnabled() bool {
+	if cfg.NetworkThreshold > 0 {
+		return true
+	}
+	if cfg.Compliance.Mode == ComplianceModeP95 && cfg.Compliance.NetworkFloor > 0 {
+		return true
+	}
+	for _, rule := range cfg.Schedules {
+		if rule.Network != nil && *rule.Network > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// validateAPIListen 控制接口只允许监听本机地址或 Unix socket
+func validateAPIListen(listen string) error {
+	if strings.HasPrefix(listen, APIUnixPrefix) {
+		if strings.TrimPrefix(listen, APIUnixPrefix) == "" {
+			return fmt.Errorf("控制接口 Unix socket 路径不能为空")
+		}
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		return fmt.Errorf("无效的控制接口地址: %s", listen)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("控制接口只能监听本机地址，当前值: %s", listen)
+	}
+	return nil
+}
+
+func validatePID(name string, p PIDConfig) error {
+	if p.Kp < 0 || p.Ki < 0 || p.Kd < 0 {
+		return fmt.Errorf("%s控制器增益不能为负数，当前值: kp=%g ki=%g kd=%g", name, p.Kp, p.Ki, p.Kd)
+	}
+	if p.Kp == 0 && p.Ki == 0 {
+		return fmt.Errorf("%s控制器的 kp 和 ki 不能同时为 0", name)
+	}
+	return nil
+}
+
+// ReloadConfig 重新读取并校验配置文件，文件不存在时返回错误而不是创建默认配置
 func ReloadConfig(filepath string) (*Config, error) {
-	return LoadConfig(filepath)
+	if _, err := os.Stat(filepath); err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	cfg, err := LoadConfig(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("配置验证失败: %w", err)
+	}
+
+	return cfg, nil
 }
 
 func UpdateConfig(filepath string, updateFn func(*Config)) error {
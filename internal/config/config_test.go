@@ -0,0 +1,29 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSaveConfigRoundTripsStrings(t *testing.T) {
+	cfg := GetDefaultConfig()
+	cfg.Log.Path = `C:\Users\mika\logs\mikaboom.log`
+	cfg.History.Path = `D:\数据\history.jsonl`
+	cfg.Disk.Directory = `\\server\share\"tmp"`
+	cfg.API.Token = "a\"b\\c\td#e: f"
+	cfg.Network.Endpoint = "example.com:9000 # not a comment"
+	cfg.Schedules = []ScheduleConfig{}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := SaveConfig(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, cfg) {
+		t.Fatalf("config changed after save and load:\n got %+v\nwant %+v", got, cfg)
+	}
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// reloadDebounce 文件变化后等待的时间，编辑器保存时通常会连续触发多次写入
+const reloadDebounce = 300 * time.Millisecond
+
+// pollInterval 不支持文件事件的平台上检查修改时间的间隔
+const pollInterval = 2 * time.Second
+
+// Watcher 监视配置文件变化，连续的多次修改合并为一次通知
+type Watcher struct {
+	path    string
+	raw     chan struct{}
+	changes chan struct{}
+	done    chan struct{}
+	stop    func()
+	once    sync.Once
+	wg      sync.WaitGroup
+}
+
+// NewWatcher 开始监视 path，文件被修改、替换或重新创建时从 Changes 收到通知
+func NewWatcher(path string) (*Watcher, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("解析配置文件路径失败: %w", err)
+	}
+
+	w := &Watcher{
+		path:    abs,
+		raw:     make(chan struct{}, 1),
+		changes: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+
+	stop, err := watchFile(w)
+	if err != nil {
+		return nil, fmt.Errorf("监视配置文件失败: %w", err)
+	}
+	w.stop = stop
+
+	w.wg.Add(1)
+	go w.debounce()
+	return w, nil
+}
+
+// Changes 返回变化通知 channel
+func (w *Watcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// Close 停止监视
+func (w *Watcher) Close() {
+	w.once.Do(func() {
+		close(w.done)
+		if w.stop != nil {
+			w.stop()
+		}
+	})
+	w.wg.Wait()
+}
+
+// trigger 由平台实现在检测到变化时调用
+func (w *Watcher) trigger() {
+	select {
+	case w.raw <- struct{}{}:
+	default:
+	}
+}
+
+func (w *Watcher) debounce() {
+	defer w.wg.Done()
+
+	timer := time.NewTimer(reloadDebounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.raw:
+			timer.Reset(reloadDebounce)
+		case <-timer.C:
+			select {
+			case w.changes <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// poll 定时比较文件的修改时间和大小
+func (w *Watcher) poll() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	last := fileStamp(w.path)
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			if stamp := fileStamp(w.path); stamp != last {
+				last = stamp
+				w.trigger()
+			}
+		}
+	}
+}
+
+func fileStamp(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size())
+}
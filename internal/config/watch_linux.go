@@ -0,0 +1,65 @@
+//go:build linux
+
+package config
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// watchFile 使用 inotify 监视配置文件所在目录
+// 监视目录而不是文件本身，编辑器先写临时文件再重命名的保存方式也能被检测到
+func watchFile(w *Watcher) (func(), error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+
+	mask := uint32(unix.IN_CLOSE_WRITE | unix.IN_MODIFY | unix.IN_MOVED_TO | unix.IN_CREATE | unix.IN_DELETE)
+	if _, err := unix.InotifyAddWatch(fd, filepath.Dir(w.path), mask); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+
+	// 非阻塞描述符交给运行时轮询，Close 时阻塞中的 Read 会立即返回
+	f := os.NewFile(uintptr(fd), "inotify")
+	name := filepath.Base(w.path)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		buf := make([]byte, 4096)
+		for {
+			n, err := f.Read(buf)
+			if err != nil {
+				select {
+				case <-w.done:
+				default:
+					log.Printf("读取配置文件事件失败: %v", err)
+				}
+				return
+			}
+
+			for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+				event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+				start := offset + unix.SizeofInotifyEvent
+				end := start + int(event.Len)
+				if end > n {
+					break
+				}
+				if string(bytes.TrimRight(buf[start:end], "\x00")) == name {
+					w.trigger()
+				}
+				offset = end
+			}
+		}
+	}()
+
+	return func() { f.Close() }, nil
+}
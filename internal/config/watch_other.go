@@ -0,0 +1,10 @@
+//go:build !linux
+
+package config
+
+// watchFile 当前平台使用轮询检查文件变化
+func watchFile(w *Watcher) (func(), error) {
+	w.wg.Add(1)
+	go w.poll()
+	return nil, nil
+}
@@ -0,0 +1,358 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"MikaBooM/internal/config"
+	"MikaBooM/internal/resource"
+)
+
+// Notifier 工作器状态变化通知
+type Notifier interface {
+	NotifyWorkStart(label string, threshold int, unit string)
+	NotifyWorkStop(label string)
+}
+
+// Policy 阈值策略，在配置阈值基础上计算本轮实际生效的阈值
+// 多个策略按顺序依次作用，前一个策略的结果作为后一个的输入
+type Policy interface {
+	Threshold(name string, threshold int, otherUsage float64, now time.Time) int
+	// Observe 在每轮决策后接收资源状态，可用于记录历史
+	Observe(state ResourceState, now time.Time)
+}
+
+// Describer 策略可选实现，返回当前生效状态的简短说明，用于控制台和托盘显示
+type Describer interface {
+	Describe(now time.Time) string
+}
+
+// Clock 返回当前时间，测试时可替换为固定时钟
+type Clock func() time.Time
+
+// Options 控制器依赖
+type Options struct {
+	Registry *resource.Registry
+	Notifier Notifier
+	Clock    Clock
+	Policies []Policy
+}
+
+// ResourceState 单个资源在一次决策后的状态
+type ResourceState struct {
+	Name        string
+	Usage       float64 // 系统总占用
+	WorkerUsage float64 // 工作器自身占用
+	OtherUsage  float64 // 其他程序占用
+	Threshold   int
+	Target      float64 // 工作器目标占用
+	Active      bool    // 工作器是否应当运行
+	Paused      bool    // 工作器是否被手动暂停
+}
+
+// Event 工作器启停事件
+type Event struct {
+	Resource   string
+	Started    bool
+	OtherUsage float64
+	Threshold  int
+}
+
+// Snapshot 一次决策的完整结果，Resources 与注册顺序一致
+type Snapshot struct {
+	Time      time.Time
+	Resources []ResourceState
+	Events    []Event
+	Notes     []string // 策略状态说明
+}
+
+// Get 按名称查找资源状态
+func (s Snapshot) Get(name string) (ResourceState, bool) {
+	for _, r := range s.Resources {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return ResourceState{}, false
+}
+
+// Controller 持有阈值决策逻辑，每次 Tick 完成一轮 采样 -> 决策 -> 调整
+type Controller struct {
+	mu       sync.RWMutex
+	cfg      *config.Config
+	registry *resource.Registry
+	notifier Notifier
+	clock    Clock
+	policies []Policy
+	active   map[string]bool
+	paused   map[string]bool
+	override map[string]int // 运行时设置的阈值，优先于配置文件和阈值策略
+	last     Snapshot
+	hasLast  bool
+}
+
+func New(cfg *config.Config, opts Options) *Controller {
+	clock := opts.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+
+	notifier := opts.Notifier
+	if notifier == nil {
+		notifier = nopNotifier{}
+	}
+
+	registry := opts.Registry
+	if registry == nil {
+		registry = resource.NewRegistry()
+	}
+
+	return &Controller{
+		cfg:      cfg,
+		registry: registry,
+		notifier: notifier,
+		clock:    clock,
+		policies: opts.Policies,
+		active:   make(map[string]bool),
+		paused:   make(map[string]bool),
+		override: make(map[string]int),
+	}
+}
+
+// Tick 执行一轮决策
+// 某个资源采样失败时只跳过该资源，其工作器保持原状态，其余资源照常决策；
+// 返回的错误合并了各资源的采样错误，此时快照仍然有效，只是不包含失败的资源
+func (c *Controller) Tick() (Snapshot, error) {
+	var resources []*resource.Resource
+	var usages []float64
+	var errs []error
+	for _, r := range c.registry.All() {
+		usage, err := r.Source.GetUsage()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("获取%s使用率失败: %w", r.Label, err))
+			continue
+		}
+		resources = append(resources, r)
+		usages = append(usages, usage)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snap := Snapshot{
+		Time:      c.clock(),
+		Resources: make([]ResourceState, 0, len(resources)),
+	}
+	for i, r := range resources {
+		state := c.decide(r, usages[i], snap.Time, &snap.Events)
+		for _, p := range c.policies {
+			p.Observe(state, snap.Time)
+		}
+		snap.Resources = append(snap.Resources, state)
+	}
+
+	for _, p := range c.policies {
+		if d, ok := p.(Describer); ok {
+			if note := d.Describe(snap.Time); note != "" {
+				snap.Notes = append(snap.Notes, note)
+			}
+		}
+	}
+
+	c.last = snap
+	c.hasLast = true
+	return snap, errors.Join(errs...)
+}
+
+func (c *Controller) decide(r *resource.Resource, usage float64, now time.Time, events *[]Event) ResourceState {
+	state := ResourceState{
+		Name:  r.Name,
+		Usage: usage,
+	}
+
+	workerUsage := 0.0
+	if r.Loader != nil {
+		workerUsage = r.Loader.GetUsage()
+	}
+	otherUsage := usage - workerUsage
+	if otherUsage < 0 {
+		otherUsage = 0
+	}
+	state.WorkerUsage = workerUsage
+	state.OtherUsage = otherUsage
+
+	threshold := r.Threshold(c.cfg)
+	for _, p := range c.policies {
+		threshold = p.Threshold(r.Name, threshold, otherUsage, now)
+	}
+	// 运行时阈值是操作者的明确指令，最后应用，不会被计划等策略覆盖
+	if value, ok := c.override[r.Name]; ok {
+		threshold = value
+	}
+	state.Threshold = threshold
+
+	if r.Loader == nil {
+		return state
+	}
+
+	state.Paused = c.paused[r.Name]
+	shouldWork := otherUsage < float64(threshold) && !state.Paused
+	if shouldWork != c.active[r.Name] {
+		if shouldWork {
+			r.Loader.Start()
+			c.notifier.NotifyWorkStart(r.Label, threshold, r.Unit)
+		} else {
+			r.Loader.Stop()
+			c.notifier.NotifyWorkStop(r.Label)
+		}
+		c.active[r.Name] = shouldWork
+		*events = append(*events, Event{
+			Resource:   r.Name,
+			Started:    shouldWork,
+			OtherUsage: otherUsage,
+			Threshold:  threshold,
+		})
+	}
+	state.Active = shouldWork
+
+	if shouldWork {
+		target := float64(threshold) - otherUsage
+		if target < 0 {
+			target = 0
+		}
+		if r.MaxTarget > 0 && target > r.MaxTarget {
+			target = r.MaxTarget
+		}
+		state.Target = target
+		r.Loader.AdjustLoad(workerUsage, target)
+	}
+
+	return state
+}
+
+// Registry 返回控制器使用的资源注册表
+func (c *Controller) Registry() *resource.Registry {
+	return c.registry
+}
+
+// Latest 返回最近一次决策结果
+func (c *Controller) Latest() (Snapshot, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.last, c.hasLast
+}
+
+// Config 返回当前使用的配置
+func (c *Controller) Config() *config.Config {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cfg
+}
+
+// SetConfig 替换配置，下一次 Tick 生效
+func (c *Controller) SetConfig(cfg *config.Config) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cfg = cfg
+}
+
+// Apply 同时替换配置和阈值策略，下一次 Tick 生效，不会出现新配置与旧策略混用的一轮
+func (c *Controller) Apply(cfg *config.Config, policies []Policy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cfg = cfg
+	c.policies = policies
+}
+
+// Pause 暂停资源的工作器，直到 Resume 前不会再启动
+func (c *Controller) Pause(name string) error {
+	r, ok := c.registry.Get(name)
+	if !ok {
+		return fmt.Errorf("未知的资源: %s", name)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.paused[name] = true
+	if r.Loader != nil && c.active[name] {
+		r.Loader.Stop()
+		c.notifier.NotifyWorkStop(r.Label)
+		c.active[name] = false
+	}
+	return nil
+}
+
+// Resume 恢复资源的工作器，下一次 Tick 按阈值重新决定是否运行
+func (c *Controller) Resume(name string) error {
+	if _, ok := c.registry.Get(name); !ok {
+		return fmt.Errorf("未知的资源: %s", name)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.paused, name)
+	return nil
+}
+
+// IsPaused 返回资源的工作器是否被暂停
+func (c *Controller) IsPaused(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.paused[name]
+}
+
+// SetThreshold 设置运行时阈值，覆盖配置文件中的值以及计划等策略的结果，重新加载配置后仍然有效
+func (c *Controller) SetThreshold(name string, value int) error {
+	if _, ok := c.registry.Get(name); !ok {
+		return fmt.Errorf("未知的资源: %s", name)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.override[name] = value
+	return nil
+}
+
+// BaseThreshold 返回运行时阈值或配置文件中的值，不含策略调整
+// 第二个返回值表示是否为运行时设置的阈值
+func (c *Controller) BaseThreshold(name string) (int, bool) {
+	r, ok := c.registry.Get(name)
+	if !ok {
+		return 0, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if value, ok := c.override[name]; ok {
+		return value, true
+	}
+	return r.Threshold(c.cfg), false
+}
+
+// ClearThreshold 取消运行时阈值，恢复使用配置文件中的值
+func (c *Controller) ClearThreshold(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.override, name)
+}
+
+// Shutdown 停止所有工作器
+func (c *Controller) Shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, r := range c.registry.All() {
+		if r.Loader != nil {
+			r.Loader.Stop()
+		}
+		c.active[r.Name] = false
+	}
+}
+
+type nopNotifier struct{}
+
+func (nopNotifier) NotifyWorkStart(string, int, string) {}
+func (nopNotifier) NotifyWorkStop(string)               {}
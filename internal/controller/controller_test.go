@@ -0,0 +1,436 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"MikaBooM/internal/config"
+	"MikaBooM/internal/resource"
+)
+
+type fakeSource struct {
+	usage float64
+	err   error
+}
+
+func (s *fakeSource) GetUsage() (float64, error) {
+	return s.usage, s.err
+}
+
+type fakeLoader struct {
+	running  bool
+	usage    float64
+	starts   int
+	stops    int
+	adjusted bool
+	target   float64
+}
+
+func (l *fakeLoader) Start()            { l.running = true; l.starts++ }
+func (l *fakeLoader) Stop()             { l.running = false; l.stops++ }
+func (l *fakeLoader) IsRunning() bool   { return l.running }
+func (l *fakeLoader) GetUsage() float64 { return l.usage }
+
+func (l *fakeLoader) AdjustLoad(current, target float64) {
+	l.adjusted = true
+	l.target = target
+}
+
+type fakeNotifier struct {
+	started []string
+	stopped []string
+}
+
+func (n *fakeNotifier) NotifyWorkStart(label string, threshold int, unit string) {
+	n.started = append(n.started, label)
+}
+
+func (n *fakeNotifier) NotifyWorkStop(label string) {
+	n.stopped = append(n.stopped, label)
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+// fixedPolicy 将指定资源的阈值改为固定值，并记录收到的输入阈值
+type fixedPolicy struct {
+	name  string
+	value int
+	input int
+}
+
+func (p *fixedPolicy) Threshold(name string, threshold int, otherUsage float64, now time.Time) int {
+	if name != p.name {
+		return threshold
+	}
+	p.input = threshold
+	return p.value
+}
+
+func (p *fixedPolicy) Observe(state ResourceState, now time.Time) {}
+
+// windowPolicy 在 [from, to) 时间段内覆盖阈值，模拟计划规则
+type windowPolicy struct {
+	from, to time.Time
+	value    int
+}
+
+func (p *windowPolicy) Threshold(name string, threshold int, otherUsage float64, now time.Time) int {
+	if !now.Before(p.from) && now.Before(p.to) {
+		return p.value
+	}
+	return threshold
+}
+
+func (p *windowPolicy) Observe(state ResourceState, now time.Time) {}
+
+type harness struct {
+	ctrl     *Controller
+	clock    *fakeClock
+	notifier *fakeNotifier
+	cpu      *fakeSource
+	mem      *fakeSource
+	cpuLoad  *fakeLoader
+	memLoad  *fakeLoader
+}
+
+func newHarness(t *testing.T, policies ...Policy) *harness {
+	t.Helper()
+
+	h := &harness{
+		clock:    &fakeClock{now: time.Date(2026, 1, 5, 10, 0, 0, 0, time.Local)},
+		notifier: &fakeNotifier{},
+		cpu:      &fakeSource{},
+		mem:      &fakeSource{},
+		cpuLoad:  &fakeLoader{},
+		memLoad:  &fakeLoader{},
+	}
+
+	registry := resource.NewRegistry()
+	resources := []*resource.Resource{
+		{
+			Name:      resource.CPU,
+			Label:     "CPU",
+			Unit:      "%",
+			Source:    h.cpu,
+			Loader:    h.cpuLoad,
+			MaxTarget: 100,
+			Threshold: func(c *config.Config) int { return c.CPUThreshold },
+		},
+		{
+			Name:      resource.Memory,
+			Label:     "内存",
+			Unit:      "%",
+			Source:    h.mem,
+			Loader:    h.memLoad,
+			MaxTarget: 80,
+			Threshold: func(c *config.Config) int { return c.MemoryThreshold },
+		},
+	}
+	for _, r := range resources {
+		if err := registry.Register(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.GetDefaultConfig()
+	cfg.CPUThreshold = 60
+	cfg.MemoryThreshold = 60
+
+	h.ctrl = New(cfg, Options{
+		Registry: registry,
+		Notifier: h.notifier,
+		Clock:    h.clock.Now,
+		Policies: policies,
+	})
+	return h
+}
+
+func (h *harness) tick(t *testing.T) Snapshot {
+	t.Helper()
+	snap, err := h.ctrl.Tick()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return snap
+}
+
+func TestTickStartStop(t *testing.T) {
+	tests := []struct {
+		name        string
+		usage       float64
+		workerUsage float64
+		wasActive   bool
+		wantActive  bool
+		wantEvent   bool
+	}{
+		{name: "idle starts", usage: 10, wantActive: true, wantEvent: true},
+		{name: "busy stays stopped", usage: 75, wantActive: false},
+		{name: "worker usage is not other usage", usage: 70, workerUsage: 40, wasActive: true, wantActive: true},
+		{name: "other usage over threshold stops", usage: 90, workerUsage: 20, wasActive: true, wantActive: false, wantEvent: true},
+		{name: "equal to threshold stops", usage: 60, wasActive: true, wantActive: false, wantEvent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t)
+			if tt.wasActive {
+				h.cpu.usage = 0
+				h.tick(t)
+				h.notifier.started = nil
+			}
+
+			h.cpu.usage = tt.usage
+			h.cpuLoad.usage = tt.workerUsage
+			snap := h.tick(t)
+
+			state, _ := snap.Get(resource.CPU)
+			if state.Active != tt.wantActive {
+				t.Fatalf("Active = %v, want %v", state.Active, tt.wantActive)
+			}
+			if h.cpuLoad.running != tt.wantActive {
+				t.Fatalf("loader running = %v, want %v", h.cpuLoad.running, tt.wantActive)
+			}
+
+			var events []Event
+			for _, e := range snap.Events {
+				if e.Resource == resource.CPU {
+					events = append(events, e)
+				}
+			}
+			if got := len(events) == 1; got != tt.wantEvent {
+				t.Fatalf("events = %+v, want event %v", events, tt.wantEvent)
+			}
+			notified := len(h.notifier.started) + len(h.notifier.stopped)
+			if tt.wantEvent && notified == 0 {
+				t.Fatal("transition was not notified")
+			}
+		})
+	}
+}
+
+func TestTickNoRepeatedTransitions(t *testing.T) {
+	h := newHarness(t)
+	for i := 0; i < 3; i++ {
+		h.tick(t)
+	}
+	if h.cpuLoad.starts != 1 {
+		t.Fatalf("Start called %d times, want 1", h.cpuLoad.starts)
+	}
+	if len(h.notifier.started) != 2 {
+		t.Fatalf("start notifications = %v, want one per resource", h.notifier.started)
+	}
+}
+
+func TestTickClampsTarget(t *testing.T) {
+	tests := []struct {
+		name       string
+		resource   string
+		threshold  int
+		usage      float64
+		wantTarget float64
+	}{
+		{name: "cpu below cap", resource: resource.CPU, threshold: 60, usage: 20, wantTarget: 40},
+		{name: "cpu capped at 100", resource: resource.CPU, threshold: 150, usage: 10, wantTarget: 100},
+		{name: "memory below cap", resource: resource.Memory, threshold: 70, usage: 10, wantTarget: 60},
+		{name: "memory capped at 80", resource: resource.Memory, threshold: 95, usage: 5, wantTarget: 80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t)
+			if err := h.ctrl.SetThreshold(tt.resource, tt.threshold); err != nil {
+				t.Fatal(err)
+			}
+			h.cpu.usage = tt.usage
+			h.mem.usage = tt.usage
+
+			snap := h.tick(t)
+			state, _ := snap.Get(tt.resource)
+			if state.Target != tt.wantTarget {
+				t.Fatalf("Target = %v, want %v", state.Target, tt.wantTarget)
+			}
+
+			loader := h.cpuLoad
+			if tt.resource == resource.Memory {
+				loader = h.memLoad
+			}
+			if !loader.adjusted || loader.target != tt.wantTarget {
+				t.Fatalf("AdjustLoad target = %v (called %v), want %v", loader.target, loader.adjusted, tt.wantTarget)
+			}
+		})
+	}
+}
+
+func TestTickSourceErrorSkipsOnlyThatResource(t *testing.T) {
+	h := newHarness(t)
+	h.tick(t)
+	if !h.cpuLoad.running || !h.memLoad.running {
+		t.Fatal("workers did not start on an idle system")
+	}
+
+	h.mem.err = errors.New("boom")
+	h.cpu.usage = 90
+	snap, err := h.ctrl.Tick()
+	if !errors.Is(err, h.mem.err) {
+		t.Fatalf("Tick error = %v, want the memory source error", err)
+	}
+	if h.cpuLoad.running {
+		t.Fatal("cpu worker was not stopped while another source failed")
+	}
+	if !h.memLoad.running || h.memLoad.stops != 0 {
+		t.Fatal("worker of the failed resource was touched")
+	}
+	if _, ok := snap.Get(resource.CPU); !ok {
+		t.Fatal("cpu missing from snapshot")
+	}
+	if _, ok := snap.Get(resource.Memory); ok {
+		t.Fatal("failed resource included in snapshot")
+	}
+	if latest, ok := h.ctrl.Latest(); !ok || !latest.Time.Equal(snap.Time) {
+		t.Fatal("partial tick was not recorded as latest")
+	}
+}
+
+func TestThresholdOverride(t *testing.T) {
+	h := newHarness(t)
+	h.cpu.usage = 50
+
+	snap := h.tick(t)
+	if state, _ := snap.Get(resource.CPU); !state.Active {
+		t.Fatal("worker should run below configured threshold")
+	}
+
+	if err := h.ctrl.SetThreshold(resource.CPU, 40); err != nil {
+		t.Fatal(err)
+	}
+	if value, override := h.ctrl.BaseThreshold(resource.CPU); value != 40 || !override {
+		t.Fatalf("BaseThreshold = %d, %v, want 40, true", value, override)
+	}
+	snap = h.tick(t)
+	if state, _ := snap.Get(resource.CPU); state.Active || state.Threshold != 40 {
+		t.Fatalf("override not applied: %+v", state)
+	}
+
+	// 重新加载配置后运行时阈值仍然有效
+	cfg := config.GetDefaultConfig()
+	cfg.CPUThreshold = 90
+	h.ctrl.SetConfig(cfg)
+	snap = h.tick(t)
+	if state, _ := snap.Get(resource.CPU); state.Threshold != 40 {
+		t.Fatalf("override lost on config reload: threshold %d", state.Threshold)
+	}
+
+	h.ctrl.ClearThreshold(resource.CPU)
+	if value, override := h.ctrl.BaseThreshold(resource.CPU); value != 90 || override {
+		t.Fatalf("BaseThreshold after clear = %d, %v, want 90, false", value, override)
+	}
+	snap = h.tick(t)
+	if state, _ := snap.Get(resource.CPU); !state.Active || state.Threshold != 90 {
+		t.Fatalf("cleared override still in effect: %+v", state)
+	}
+
+	if err := h.ctrl.SetThreshold("gpu", 10); err == nil {
+		t.Fatal("SetThreshold accepted an unknown resource")
+	}
+}
+
+func TestPolicyWindowExpires(t *testing.T) {
+	h := newHarness(t)
+	start := h.clock.now
+	h.ctrl.Apply(h.ctrl.Config(), []Policy{&windowPolicy{from: start, to: start.Add(time.Hour), value: 0}})
+	h.cpu.usage = 10
+
+	snap := h.tick(t)
+	if state, _ := snap.Get(resource.CPU); state.Active || state.Threshold != 0 {
+		t.Fatalf("window policy not applied: %+v", state)
+	}
+
+	h.clock.now = start.Add(time.Hour)
+	snap = h.tick(t)
+	if state, _ := snap.Get(resource.CPU); !state.Active || state.Threshold != 60 {
+		t.Fatalf("window policy still applied after it ended: %+v", state)
+	}
+	if !snap.Time.Equal(h.clock.now) {
+		t.Fatalf("snapshot time %v, want injected clock %v", snap.Time, h.clock.now)
+	}
+}
+
+func TestPolicyOrder(t *testing.T) {
+	first := &fixedPolicy{name: resource.CPU, value: 30}
+	second := &fixedPolicy{name: resource.CPU, value: 50}
+	h := newHarness(t, first, second)
+
+	snap := h.tick(t)
+	state, _ := snap.Get(resource.CPU)
+	if first.input != 60 {
+		t.Fatalf("first policy saw %d, want configured 60", first.input)
+	}
+	if second.input != 30 {
+		t.Fatalf("second policy saw %d, want first policy's 30", second.input)
+	}
+	if state.Threshold != 50 {
+		t.Fatalf("Threshold = %d, want last policy's 50", state.Threshold)
+	}
+	if mem, _ := snap.Get(resource.Memory); mem.Threshold != 60 {
+		t.Fatalf("policy for cpu changed memory threshold to %d", mem.Threshold)
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	h := newHarness(t)
+	h.tick(t)
+
+	if err := h.ctrl.Pause(resource.CPU); err != nil {
+		t.Fatal(err)
+	}
+	if h.cpuLoad.running {
+		t.Fatal("Pause did not stop the worker")
+	}
+	snap := h.tick(t)
+	if state, _ := snap.Get(resource.CPU); state.Active || !state.Paused {
+		t.Fatalf("paused worker restarted: %+v", state)
+	}
+
+	if err := h.ctrl.Resume(resource.CPU); err != nil {
+		t.Fatal(err)
+	}
+	snap = h.tick(t)
+	if state, _ := snap.Get(resource.CPU); !state.Active {
+		t.Fatalf("worker not restarted after resume: %+v", state)
+	}
+}
+
+func TestOverrideTakesPrecedenceOverPolicies(t *testing.T) {
+	h := newHarness(t)
+	start := h.clock.now
+	schedule := &windowPolicy{from: start, to: start.Add(time.Hour), value: 20}
+	h.ctrl.Apply(h.ctrl.Config(), []Policy{schedule, &fixedPolicy{name: resource.Memory, value: 10}})
+	h.cpu.usage = 30
+
+	snap := h.tick(t)
+	if state, _ := snap.Get(resource.CPU); state.Active || state.Threshold != 20 {
+		t.Fatalf("schedule not applied before override: %+v", state)
+	}
+
+	if err := h.ctrl.SetThreshold(resource.CPU, 50); err != nil {
+		t.Fatal(err)
+	}
+	snap = h.tick(t)
+	if state, _ := snap.Get(resource.CPU); !state.Active || state.Threshold != 50 {
+		t.Fatalf("active schedule replaced the runtime threshold: %+v", state)
+	}
+	if state, _ := snap.Get(resource.Memory); state.Threshold != 10 {
+		t.Fatalf("override on cpu changed memory policy result: %+v", state)
+	}
+
+	h.ctrl.ClearThreshold(resource.CPU)
+	snap = h.tick(t)
+	if state, _ := snap.Get(resource.CPU); state.Threshold != 20 {
+		t.Fatalf("schedule not restored after clearing override: %+v", state)
+	}
+}
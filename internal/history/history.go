@@ -0,0 +1,226 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"MikaBooM/internal/controller"
+	"MikaBooM/internal/rotate"
+)
+
+// maxTickGap 单次采样计入的最长时间，休眠或暂停后的空档不计入
+const maxTickGap = time.Minute
+
+// Entry 一个资源在一个记录周期内的汇总，每条占一行 JSON
+type Entry struct {
+	Time          time.Time `json:"time"` // 周期结束时间
+	Resource      string    `json:"resource"`
+	Unit          string    `json:"unit"`
+	Seconds       float64   `json:"seconds"` // 周期内计入的采样时长
+	Usage         float64   `json:"usage"`   // 系统总占用（按时长加权平均，下同）
+	Worker        float64   `json:"worker"`  // 工作器占用
+	Other         float64   `json:"other"`   // 其他程序占用
+	Threshold     int       `json:"threshold"`
+	AboveSeconds  float64   `json:"above_seconds"`      // 系统总占用超过阈值的时长
+	ActiveSeconds float64   `json:"active_seconds"`     // 工作器运行的时长
+	Capacity      float64   `json:"capacity,omitempty"` // 占用率 100% 对应的量，CPU 为周期结束时的核心数（含 cgroup 上限）
+	Samples       []float64 `json:"samples,omitempty"`  // 周期内每次采样的系统总占用，用于计算百分位和最大值
+}
+
+type accumulator struct {
+	unit      string
+	seconds   float64
+	usage     float64
+	worker    float64
+	other     float64
+	threshold int
+	above     float64
+	active    float64
+	samples   []float64
+}
+
+// Recorder 将控制器的决策结果按周期汇总后写入轮转的 JSONL 文件
+type Recorder struct {
+	mu       sync.Mutex
+	out      *rotate.Writer
+	interval time.Duration
+	units    map[string]string
+	capacity map[string]func() float64
+	acc      map[string]*accumulator
+	order    []string
+	start    time.Time
+	lastTick time.Time
+}
+
+// DefaultPath 返回配置文件同级目录下的历史文件路径
+func DefaultPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "history", "history.jsonl")
+}
+
+// NewRecorder 创建记录器，units 为各资源的单位，每 interval 写入一次汇总
+func NewRecorder(path string, interval time.Duration, maxSizeMB, maxFiles int, units map[string]string) (*Recorder, error) {
+	out, err := rotate.Open(path, int64(maxSizeMB)*1024*1024, maxFiles)
+	if err != nil {
+		return nil, fmt.Errorf("打开历史文件失败: %w", err)
+	}
+
+	return &Recorder{
+		out:      out,
+		interval: interval,
+		units:    units,
+		capacity: make(map[string]func() float64),
+		acc:      make(map[string]*accumulator),
+	}, nil
+}
+
+// SetCapacity 设置资源占用率 100% 对应的量，每个周期写入时读取一次
+func (r *Recorder) SetCapacity(name string, capacity func() float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.capacity[name] = capacity
+}
+
+// Record 累加一轮决策结果，周期结束时写入文件
+func (r *Recorder) Record(snap controller.Snapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.lastTick.IsZero() {
+		r.lastTick = snap.Time
+		r.start = snap.Time
+		return nil
+	}
+
+	dt := snap.Time.Sub(r.lastTick)
+	r.lastTick = snap.Time
+	if dt <= 0 {
+		return nil
+	}
+	if dt > maxTickGap {
+		dt = maxTickGap
+	}
+	sec := dt.Seconds()
+
+	for _, s := range snap.Resources {
+		a := r.acc[s.Name]
+		if a == nil {
+			a = &accumulator{unit: r.units[s.Name]}
+			r.acc[s.Name] = a
+			r.order = append(r.order, s.Name)
+		}
+		a.seconds += sec
+		a.usage += s.Usage * sec
+		a.worker += s.WorkerUsage * sec
+		a.other += s.OtherUsage * sec
+		a.threshold = s.Threshold
+		if s.Usage > float64(s.Threshold) {
+			a.above += sec
+		}
+		if s.Active {
+			a.active += sec
+		}
+		a.samples = append(a.samples, round(s.Usage))
+	}
+
+	if snap.Time.Sub(r.start) >= r.interval {
+		return r.flush(snap.Time)
+	}
+	return nil
+}
+
+func (r *Recorder) flush(now time.Time) error {
+	r.start = now
+
+	var buf []byte
+	for _, name := range r.order {
+		a := r.acc[name]
+		if a.seconds == 0 {
+			continue
+		}
+
+		capacity := 0.0
+		if f := r.capacity[name]; f != nil {
+			capacity = round(f())
+		}
+
+		line, err := json.Marshal(Entry{
+			Time:          now.UTC().Truncate(time.Second),
+			Resource:      name,
+			Unit:          a.unit,
+			Seconds:       round(a.seconds),
+			Usage:         round(a.usage / a.seconds),
+			Worker:        round(a.worker / a.seconds),
+			Other:         round(a.other / a.seconds),
+			Threshold:     a.threshold,
+			AboveSeconds:  round(a.above),
+			ActiveSeconds: round(a.active),
+			Capacity:      capacity,
+			Samples:       a.samples,
+		})
+		if err != nil {
+			return err
+		}
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+		*a = accumulator{unit: a.unit}
+	}
+
+	if len(buf) == 0 {
+		return nil
+	}
+	if _, err := r.out.Write(buf); err != nil {
+		return fmt.Errorf("写入历史文件失败: %w", err)
+	}
+	return nil
+}
+
+// Close 写入未满一个周期的数据并关闭文件
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.flush(r.lastTick)
+	if cerr := r.out.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Read 按时间顺序读取 path 及其轮转文件中 since 之后的记录
+func Read(path string, since time.Time) ([]Entry, error) {
+	var entries []Entry
+	for _, name := range rotate.Files(path) {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, fmt.Errorf("读取历史文件失败: %w", err)
+		}
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var e Entry
+			// 异常退出时最后一行可能不完整，跳过无法解析的行
+			if json.Unmarshal(scanner.Bytes(), &e) != nil {
+				continue
+			}
+			if !e.Time.Before(since) {
+				entries = append(entries, e)
+			}
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("读取历史文件失败: %w", err)
+		}
+	}
+	return entries, nil
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,122 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"MikaBooM/internal/controller"
+)
+
+func TestSummarizeUsesRawSamples(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	rec, err := NewRecorder(path, time.Minute, 1, 2, map[string]string{"cpu": "%"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 两分钟内每 2 秒采样一次，其中 10% 的采样为 90%，其余为 10%
+	start := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	for i := 0; i <= 60; i++ {
+		usage := 10.0
+		if i%10 == 0 {
+			usage = 90
+		}
+		snap := controller.Snapshot{
+			Time: start.Add(time.Duration(i) * 2 * time.Second),
+			Resources: []controller.ResourceState{
+				{Name: "cpu", Usage: usage, Threshold: 50},
+			},
+		}
+		if err := rec.Record(snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Read(path, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want one per minute", len(entries))
+	}
+
+	summaries := Summarize(entries)
+	if len(summaries) != 1 {
+		t.Fatalf("got %d summaries, want 1", len(summaries))
+	}
+	s := summaries[0]
+	if s.Max != 90 {
+		t.Errorf("Max = %v, want the 90 spike", s.Max)
+	}
+	if s.P95 != 90 {
+		t.Errorf("P95 = %v, want 90 from raw samples", s.P95)
+	}
+	if s.P50 != 10 {
+		t.Errorf("P50 = %v, want 10", s.P50)
+	}
+	if s.AboveSeconds != 12 {
+		t.Errorf("AboveSeconds = %v, want 12", s.AboveSeconds)
+	}
+}
+
+func TestSummarizeCapacityPerEntry(t *testing.T) {
+	// 前一小时有 4 核，之后 cgroup 上限降为 2 核，工作器都占 50%
+	entries := []Entry{
+		{Resource: "cpu", Seconds: 3600, Worker: 50, Capacity: 4, Samples: []float64{50}},
+		{Resource: "cpu", Seconds: 3600, Worker: 50, Capacity: 2, Samples: []float64{50}},
+	}
+	s := Summarize(entries)[0]
+	if s.CapacityTotal != 3*3600 {
+		t.Errorf("CapacityTotal = %v, want 3 core-hours", s.CapacityTotal)
+	}
+	if s.Capacity != 2 {
+		t.Errorf("Capacity = %v, want the latest entry's 2", s.Capacity)
+	}
+}
+
+func TestRecorderWritesCapacity(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	rec, err := NewRecorder(path, time.Minute, 1, 2, map[string]string{"cpu": "%", "memory": "%"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.SetCapacity("cpu", func() float64 { return 1.5 })
+
+	start := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	for i := 0; i <= 30; i++ {
+		snap := controller.Snapshot{
+			Time: start.Add(time.Duration(i) * 2 * time.Second),
+			Resources: []controller.ResourceState{
+				{Name: "cpu", Usage: 20},
+				{Name: "memory", Usage: 20},
+			},
+		}
+		if err := rec.Record(snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Read(path, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want one per resource", len(entries))
+	}
+	for _, e := range entries {
+		want := 0.0
+		if e.Resource == "cpu" {
+			want = 1.5
+		}
+		if e.Capacity != want {
+			t.Errorf("%s capacity = %v, want %v", e.Resource, e.Capacity, want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package history
+
+import "MikaBooM/internal/stats"
+
+// Summary 一个资源在报告时间段内的统计
+type Summary struct {
+	Resource      string
+	Unit          string
+	Seconds       float64 // 有记录的总时长
+	Mean          float64 // 系统总占用（按时长加权平均）
+	P50           float64
+	P95           float64
+	P99           float64
+	Max           float64
+	OtherMean     float64 // 其他程序平均占用
+	WorkerMean    float64 // 工作器平均占用
+	AboveSeconds  float64 // 系统总占用超过阈值的时长
+	ActiveSeconds float64 // 工作器运行的时长
+	WorkerTotal   float64 // 工作器占用对时间的积分（单位·秒）
+	Capacity      float64 // 最后一条记录的容量，CPU 为核心数
+	CapacityTotal float64 // 工作器占用按各条记录的容量换算后对时间的积分，CPU 为核·秒
+}
+
+// Summarize 按资源汇总记录，结果按资源首次出现的顺序排列
+// 百分位和最大值基于每次采样计算
+func Summarize(entries []Entry) []Summary {
+	index := make(map[string]int)
+	var summaries []Summary
+	usages := make(map[string][]float64)
+
+	for _, e := range entries {
+		i, ok := index[e.Resource]
+		if !ok {
+			i = len(summaries)
+			index[e.Resource] = i
+			summaries = append(summaries, Summary{Resource: e.Resource, Unit: e.Unit})
+		}
+
+		s := &summaries[i]
+		s.Seconds += e.Seconds
+		s.Mean += e.Usage * e.Seconds
+		s.OtherMean += e.Other * e.Seconds
+		s.WorkerTotal += e.Worker * e.Seconds
+		s.CapacityTotal += e.Worker / 100 * e.Capacity * e.Seconds
+		s.Capacity = e.Capacity
+		s.AboveSeconds += e.AboveSeconds
+		s.ActiveSeconds += e.ActiveSeconds
+		usages[e.Resource] = append(usages[e.Resource], e.Samples...)
+	}
+
+	for i := range summaries {
+		s := &summaries[i]
+		if s.Seconds > 0 {
+			s.Mean /= s.Seconds
+			s.OtherMean /= s.Seconds
+			s.WorkerMean = s.WorkerTotal / s.Seconds
+		}
+
+		values := usages[s.Resource]
+		s.P50 = stats.NearestRank(values, 0.50)
+		s.P95 = stats.NearestRank(values, 0.95)
+		s.P99 = stats.NearestRank(values, 0.99)
+		s.Max = stats.NearestRank(values, 1)
+	}
+	return summaries
+}
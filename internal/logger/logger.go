@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"MikaBooM/internal/rotate"
+)
+
+// Options 日志设置
+type Options struct {
+	Level      string    // debug / info / warn / error
+	Format     string    // text / json，只作用于日志文件
+	Path       string    // 日志文件路径，空为不写文件
+	MaxSizeMB  int       // 单个文件大小上限，超过后轮转
+	MaxFiles   int       // 保留的轮转文件数
+	MaxAgeDays int       // 轮转文件保留天数，0 为不按时间删除
+	Console    io.Writer // 标准库 log 包的输出同时写到这里，nil 为不输出
+}
+
+// DefaultPath 返回配置文件同级目录下的日志文件路径
+func DefaultPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "logs", "mikaboom.log")
+}
+
+// ParseLevel 解析日志级别
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("未知的日志级别: %s (可选 debug、info、warn、error)", level)
+}
+
+// Setup 按设置创建日志并设为默认 slog 日志
+// slog 记录只写入日志文件，调用方在控制台上另有彩色提示，不再重复输出；
+// 标准库 log 包的输出（各模块的诊断信息）以 info 级别写入日志文件，同时输出到 Console
+// 返回的函数用于关闭日志文件
+func Setup(opts Options) (func() error, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	handlerOpts := &slog.HandlerOptions{Level: level}
+
+	var handlers []slog.Handler
+	closeFn := func() error { return nil }
+
+	if opts.Path != "" {
+		w, err := rotate.Open(opts.Path, int64(opts.MaxSizeMB)*1024*1024, opts.MaxFiles)
+		if err != nil {
+			return nil, fmt.Errorf("打开日志文件失败: %w", err)
+		}
+		w.SetMaxAge(time.Duration(opts.MaxAgeDays) * 24 * time.Hour)
+		closeFn = w.Close
+
+		if strings.ToLower(opts.Format) == "json" {
+			handlers = append(handlers, slog.NewJSONHandler(w, handlerOpts))
+		} else {
+			handlers = append(handlers, slog.NewTextHandler(w, handlerOpts))
+		}
+	}
+
+	slog.SetDefault(slog.New(fanout(handlers)))
+
+	if opts.Console != nil {
+		handlers = append(handlers, slog.NewTextHandler(opts.Console, handlerOpts))
+		log.SetOutput(slog.NewLogLogger(fanout(handlers), slog.LevelInfo).Writer())
+	}
+	return closeFn, nil
+}
+
+// fanout 将记录分发给多个处理器，没有处理器时丢弃所有记录
+func fanout(handlers []slog.Handler) slog.Handler {
+	if len(handlers) == 1 {
+		return handlers[0]
+	}
+	return multiHandler(handlers)
+}
+
+type multiHandler []slog.Handler
+
+func (m multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var first error
+	for _, h := range m {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func (m multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := make(multiHandler, len(m))
+	for i, h := range m {
+		next[i] = h.WithAttrs(attrs)
+	}
+	return next
+}
+
+func (m multiHandler) WithGroup(name string) slog.Handler {
+	next := make(multiHandler, len(m))
+	for i, h := range m {
+		next[i] = h.WithGroup(name)
+	}
+	return next
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetupConsoleShowsOnlyLogPackage(t *testing.T) {
+	defaultLogger, logOutput, logFlags := slog.Default(), log.Writer(), log.Flags()
+	t.Cleanup(func() {
+		slog.SetDefault(defaultLogger)
+		log.SetOutput(logOutput)
+		log.SetFlags(logFlags)
+	})
+
+	path := filepath.Join(t.TempDir(), "test.log")
+	var console bytes.Buffer
+	closeLog, err := Setup(Options{Path: path, MaxSizeMB: 1, MaxFiles: 1, Console: &console})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slog.Info("工作器启动", "resource", "cpu")
+	log.Printf("锁定内存失败: %v", os.ErrPermission)
+	closeLog()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := string(data)
+	for _, msg := range []string{"工作器启动", "锁定内存失败"} {
+		if !strings.Contains(file, msg) {
+			t.Errorf("日志文件中缺少 %q:\n%s", msg, file)
+		}
+	}
+
+	out := console.String()
+	if strings.Contains(out, "工作器启动") {
+		t.Errorf("slog 记录不应输出到控制台:\n%s", out)
+	}
+	if strings.Count(out, "锁定内存失败") != 1 {
+		t.Errorf("log 包的输出应在控制台出现一次:\n%s", out)
+	}
+}
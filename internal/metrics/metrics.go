@@ -0,0 +1,266 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"MikaBooM/internal/controller"
+	"MikaBooM/internal/version"
+)
+
+// Collector 汇总控制器状态并以 Prometheus 文本格式输出
+type Collector struct {
+	ctrl *controller.Controller
+
+	mu          sync.Mutex
+	transitions map[transition]uint64
+	errors      uint64
+}
+
+type transition struct {
+	resource string
+	started  bool
+}
+
+func NewCollector(ctrl *controller.Controller) *Collector {
+	return &Collector{
+		ctrl:        ctrl,
+		transitions: make(map[transition]uint64),
+	}
+}
+
+// Record 统计一轮决策中的工作器启停次数
+func (c *Collector) Record(snap controller.Snapshot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, e := range snap.Events {
+		c.transitions[transition{resource: e.Resource, started: e.Started}]++
+	}
+}
+
+// RecordError 统计监控采样失败次数
+func (c *Collector) RecordError() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errors++
+}
+
+// Write 输出所有指标
+func (c *Collector) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	e := &encoder{w: bw}
+
+	snap, _ := c.ctrl.Latest()
+	cfg := c.ctrl.Config()
+	resources := c.ctrl.Registry().All()
+
+	e.family("mikaboom_build_info", "gauge", "构建信息，值恒为 1")
+	e.sample("mikaboom_build_info", labels("version", version.GetVersion()), 1)
+
+	e.family("mikaboom_version_expire_days", "gauge", "距离版本过期的天数")
+	e.sample("mikaboom_version_expire_days", "", float64(version.GetDaysUntilExpire()))
+
+	gauges := []struct {
+		name, help string
+		value      func(controller.ResourceState) float64
+	}{
+		{"mikaboom_usage", "系统总占用（单位见 unit 标签）", func(s controller.ResourceState) float64 { return s.Usage }},
+		{"mikaboom_other_usage", "除工作器外其他程序的占用", func(s controller.ResourceState) float64 { return s.OtherUsage }},
+		{"mikaboom_worker_usage", "工作器自身的占用", func(s controller.ResourceState) float64 { return s.WorkerUsage }},
+		{"mikaboom_worker_target", "工作器目标占用", func(s controller.ResourceState) float64 { return s.Target }},
+	}
+	for _, g := range gauges {
+		e.family(g.name, "gauge", g.help)
+		for _, r := range resources {
+			if state, ok := snap.Get(r.Name); ok {
+				e.sample(g.name, labels("resource", r.Name, "unit", r.Unit), g.value(state))
+			}
+		}
+	}
+
+	e.family("mikaboom_threshold", "gauge", "本轮生效的阈值，包含运行时设置以及计划和合规策略的调整")
+	for _, r := range resources {
+		threshold, _ := c.ctrl.BaseThreshold(r.Name)
+		if state, ok := snap.Get(r.Name); ok {
+			threshold = state.Threshold
+		}
+		e.sample("mikaboom_threshold", labels("resource", r.Name, "unit", r.Unit), float64(threshold))
+	}
+
+	e.family("mikaboom_configured_threshold", "gauge", "配置文件中的阈值")
+	for _, r := range resources {
+		e.sample("mikaboom_configured_threshold", labels("resource", r.Name, "unit", r.Unit), float64(r.Threshold(cfg)))
+	}
+
+	e.family("mikaboom_worker_running", "gauge", "工作器是否正在运行")
+	for _, r := range resources {
+		if r.Loader != nil {
+			e.sample("mikaboom_worker_running", labels("resource", r.Name), boolValue(r.Loader.IsRunning()))
+		}
+	}
+
+	e.family("mikaboom_worker_paused", "gauge", "工作器是否被手动暂停")
+	for _, r := range resources {
+		if r.Loader != nil {
+			e.sample("mikaboom_worker_paused", labels("resource", r.Name), boolValue(c.ctrl.IsPaused(r.Name)))
+		}
+	}
+
+	e.family("mikaboom_worker_intensity_percent", "gauge", "CPU 工作器强度")
+	for _, r := range resources {
+		if l, ok := r.Loader.(interface{ GetIntensity() int32 }); ok {
+			e.sample("mikaboom_worker_intensity_percent", labels("resource", r.Name), float64(l.GetIntensity()))
+		}
+	}
+
+	e.family("mikaboom_worker_allocated_bytes", "gauge", "内存工作器已分配的字节数")
+	for _, r := range resources {
+		if l, ok := r.Loader.(interface{ GetAllocatedSize() int64 }); ok {
+			e.sample("mikaboom_worker_allocated_bytes", labels("resource", r.Name), float64(l.GetAllocatedSize()))
+		}
+	}
+
+	c.mu.Lock()
+	keys := make([]transition, 0, len(c.transitions))
+	for k := range c.transitions {
+		keys = append(keys, k)
+	}
+	// 按资源名排序，同一资源 start 在 stop 之前
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].resource != keys[j].resource {
+			return keys[i].resource < keys[j].resource
+		}
+		return keys[i].started && !keys[j].started
+	})
+
+	e.family("mikaboom_worker_transitions_total", "counter", "工作器启停次数")
+	for _, k := range keys {
+		direction := "stop"
+		if k.started {
+			direction = "start"
+		}
+		e.sample("mikaboom_worker_transitions_total", labels("resource", k.resource, "direction", direction), float64(c.transitions[k]))
+	}
+
+	e.family("mikaboom_monitor_errors_total", "counter", "监控采样失败次数")
+	e.sample("mikaboom_monitor_errors_total", "", float64(c.errors))
+	c.mu.Unlock()
+
+	if e.err != nil {
+		return e.err
+	}
+	return bw.Flush()
+}
+
+// ServeHTTP 实现 http.Handler
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "只支持 GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := c.Write(w); err != nil {
+		log.Printf("输出指标失败: %v", err)
+	}
+}
+
+// Server 指标 HTTP 服务
+type Server struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+// Listen 在 addr 上启动指标服务，指标路径为 path
+func Listen(addr, path string, c *Collector) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("启动指标服务失败: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(path, c)
+
+	s := &Server{
+		listener: listener,
+		server: &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("指标服务异常退出: %v", err)
+		}
+	}()
+	return s, nil
+}
+
+// Addr 返回实际监听的地址
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close 停止指标服务
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+// encoder 按 Prometheus 文本格式写入，记录第一个写入错误
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+func (e *encoder) family(name, typ, help string) {
+	e.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func (e *encoder) sample(name, labels string, value float64) {
+	e.printf("%s%s %s\n", name, labels, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+func (e *encoder) printf(format string, args ...interface{}) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = fmt.Fprintf(e.w, format, args...)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labels 将成对的名称和值格式化为 {name="value",...}
+func labels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `%s="%s"`, pairs[i], labelEscaper.Replace(pairs[i+1]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
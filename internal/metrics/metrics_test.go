@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"MikaBooM/internal/config"
+	"MikaBooM/internal/controller"
+	"MikaBooM/internal/resource"
+)
+
+type fakeSource struct{ usage float64 }
+
+func (s *fakeSource) GetUsage() (float64, error) { return s.usage, nil }
+
+func newTestCollector(t *testing.T) (*Collector, *controller.Controller) {
+	t.Helper()
+
+	registry := resource.NewRegistry()
+	for _, r := range []*resource.Resource{
+		{Name: resource.CPU, Unit: "%", Source: &fakeSource{usage: 20}, Threshold: func(c *config.Config) int { return c.CPUThreshold }},
+		{Name: resource.Memory, Unit: "%", Source: &fakeSource{usage: 30}, Threshold: func(c *config.Config) int { return c.MemoryThreshold }},
+	} {
+		if err := registry.Register(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.GetDefaultConfig()
+	cfg.CPUThreshold = 60
+	ctrl := controller.New(cfg, controller.Options{Registry: registry})
+	return NewCollector(ctrl), ctrl
+}
+
+func scrape(t *testing.T, c *Collector) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := c.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestThresholdReflectsOverride(t *testing.T) {
+	c, ctrl := newTestCollector(t)
+	if err := ctrl.SetThreshold(resource.CPU, 35); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctrl.Tick(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := scrape(t, c)
+	for _, want := range []string{
+		`mikaboom_threshold{resource="cpu",unit="%"} 35`,
+		`mikaboom_configured_threshold{resource="cpu",unit="%"} 60`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestTransitionsOrderIsStable(t *testing.T) {
+	c, _ := newTestCollector(t)
+	c.Record(controller.Snapshot{Events: []controller.Event{
+		{Resource: resource.Memory, Started: false},
+		{Resource: resource.CPU, Started: false},
+		{Resource: resource.Memory, Started: true},
+		{Resource: resource.CPU, Started: true},
+	}})
+
+	first := scrape(t, c)
+	for i := 0; i < 20; i++ {
+		if got := scrape(t, c); got != first {
+			t.Fatalf("output changed between scrapes:\n%s\n---\n%s", first, got)
+		}
+	}
+
+	var lines []string
+	for _, line := range strings.Split(first, "\n") {
+		if strings.HasPrefix(line, "mikaboom_worker_transitions_total{") {
+			lines = append(lines, line)
+		}
+	}
+	want := []string{
+		`mikaboom_worker_transitions_total{resource="cpu",direction="start"} 1`,
+		`mikaboom_worker_transitions_total{resource="cpu",direction="stop"} 1`,
+		`mikaboom_worker_transitions_total{resource="memory",direction="start"} 1`,
+		`mikaboom_worker_transitions_total{resource="memory",direction="stop"} 1`,
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("transitions =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -1,16 +1,22 @@
 package monitor
 
 import (
+	"runtime"
 	"sync"
 	"time"
 
+	"MikaBooM/internal/cgroup"
+
 	"github.com/shirou/gopsutil/v3/cpu"
 )
 
 type CPUMonitor struct {
 	mu          sync.RWMutex
 	lastUsage   float64
+	lastPerCore []float64
 	updateTime  time.Time
+	group       *cgroup.Group // 不为 nil 时按 cgroup 的 CPU 上限计算占用
+	cores       float64       // 占用率 100% 对应的核心数
 }
 
 func NewCPUMonitor() *CPUMonitor {
@@ -20,9 +26,37 @@ func NewCPUMonitor() *CPUMonitor {
 	}
 }
 
+// UseCgroup 按 cgroup 的 CPU 用量和上限计算占用，未设置上限时以全部核心为上限
+// 各核心占用仍为整机数据
+func (m *CPUMonitor) UseCgroup(g *cgroup.Group) {
+	cores, ok := g.CPULimit()
+	if !ok {
+		cores = float64(runtime.NumCPU())
+	}
+	m.group = g
+	m.cores = cores
+}
+
+// GetCapacity 返回占用率 100% 对应的核心数
+func (m *CPUMonitor) GetCapacity() float64 {
+	if m.group != nil {
+		return m.cores
+	}
+	return float64(runtime.NumCPU())
+}
+
 func (m *CPUMonitor) GetUsage() (float64, error) {
-	// 获取CPU使用率（所有核心的平均值）
-	percentages, err := cpu.Percent(time.Second, false)
+	var groupStart time.Duration
+	start := time.Now()
+	if m.group != nil {
+		var err error
+		if groupStart, err = m.group.CPUUsage(); err != nil {
+			return 0, err
+		}
+	}
+
+	// 按核心采样，同时缓存各核心占用供按核心调节使用
+	percentages, err := cpu.Percent(time.Second, true)
 	if err != nil {
 		return 0, err
 	}
@@ -31,10 +65,27 @@ func (m *CPUMonitor) GetUsage() (float64, error) {
 		return 0, nil
 	}
 
-	usage := percentages[0]
+	// 各核心采样时长相同，平均值即为整机占用
+	var usage float64
+	for _, p := range percentages {
+		usage += p
+	}
+	usage /= float64(len(percentages))
+
+	if m.group != nil {
+		groupEnd, err := m.group.CPUUsage()
+		if err != nil {
+			return 0, err
+		}
+		usage = (groupEnd - groupStart).Seconds() / (time.Since(start).Seconds() * m.cores) * 100
+		if usage > 100 {
+			usage = 100
+		}
+	}
 
 	m.mu.Lock()
 	m.lastUsage = usage
+	m.lastPerCore = percentages
 	m.updateTime = time.Now()
 	m.mu.Unlock()
 
@@ -47,6 +98,13 @@ func (m *CPUMonitor) GetCachedUsage() float64 {
 	return m.lastUsage
 }
 
+// GetCachedPerCoreUsage 返回最近一次 GetUsage 采样的各核心占用，按 CPU 编号索引
+func (m *CPUMonitor) GetCachedPerCoreUsage() []float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]float64(nil), m.lastPerCore...)
+}
+
 func (m *CPUMonitor) GetCPUCount() (int, error) {
 	count, err := cpu.Counts(true)
 	return count, err
@@ -0,0 +1,74 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// DiskMonitor 统计磁盘读写吞吐量（MB/s）
+type DiskMonitor struct {
+	mu         sync.RWMutex
+	devices    []string
+	lastUsage  float64
+	lastBytes  uint64
+	hasLast    bool
+	updateTime time.Time
+}
+
+// NewDiskMonitor 创建磁盘监控，devices 为空时统计所有整盘设备，不含分区和虚拟设备
+func NewDiskMonitor(devices []string) *DiskMonitor {
+	return &DiskMonitor{
+		devices:    devices,
+		updateTime: time.Now(),
+	}
+}
+
+func (m *DiskMonitor) GetUsage() (float64, error) {
+	devices := m.devices
+	count := true
+	if len(devices) == 0 {
+		// 非 Linux 或无法读取 /sys/block 时统计所有设备；
+		// 没有整盘设备时（例如只有虚拟磁盘的容器）不统计，否则分区和 dm 设备会与底层磁盘重复计数
+		var ok bool
+		devices, ok = wholeDisks()
+		count = !ok || len(devices) > 0
+	}
+
+	total := uint64(0)
+	if count {
+		counters, err := disk.IOCounters(devices...)
+		if err != nil {
+			return 0, err
+		}
+		for _, c := range counters {
+			total += c.ReadBytes + c.WriteBytes
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	usage := 0.0
+	if m.hasLast && total >= m.lastBytes {
+		elapsed := now.Sub(m.updateTime).Seconds()
+		if elapsed > 0 {
+			usage = float64(total-m.lastBytes) / elapsed / 1024 / 1024
+		}
+	}
+
+	m.lastBytes = total
+	m.hasLast = true
+	m.lastUsage = usage
+	m.updateTime = now
+
+	return usage, nil
+}
+
+func (m *DiskMonitor) GetCachedUsage() float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastUsage
+}
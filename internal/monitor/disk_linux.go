@@ -0,0 +1,29 @@
+//go:build linux
+
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// sysBlock 内核导出的块设备目录，只包含整盘设备，分区位于其子目录中
+const sysBlock = "/sys/block"
+
+// wholeDisks 返回有底层硬件的整盘设备名称
+// /sys/block 中没有 device 链接的是 loop、ram、zram、dm（LVM）、md 等虚拟设备，
+// 它们的读写已计入底层磁盘，一并统计会重复计数；分区不在 /sys/block 中，同样不会被统计
+// 无法读取 /sys/block 时 ok 为 false
+func wholeDisks() (names []string, ok bool) {
+	entries, err := os.ReadDir(sysBlock)
+	if err != nil {
+		return nil, false
+	}
+
+	for _, e := range entries {
+		if _, err := os.Stat(filepath.Join(sysBlock, e.Name(), "device")); err == nil {
+			names = append(names, e.Name())
+		}
+	}
+	return names, true
+}
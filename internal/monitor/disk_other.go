@@ -0,0 +1,8 @@
+//go:build !linux
+
+package monitor
+
+// wholeDisks 其他平台的计数器不包含分区与虚拟设备的重复项，ok 为 false 表示统计所有设备
+func wholeDisks() (names []string, ok bool) {
+	return nil, false
+}
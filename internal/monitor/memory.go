@@ -4,6 +4,8 @@ import (
 	"sync"
 	"time"
 
+	"MikaBooM/internal/cgroup"
+
 	"github.com/shirou/gopsutil/v3/mem"
 )
 
@@ -11,6 +13,7 @@ type MemoryMonitor struct {
 	mu         sync.RWMutex
 	lastUsage  float64
 	updateTime time.Time
+	group      *cgroup.Group // 不为 nil 时按 cgroup 的内存用量和上限计算占用
 }
 
 func NewMemoryMonitor() *MemoryMonitor {
@@ -20,6 +23,11 @@ func NewMemoryMonitor() *MemoryMonitor {
 	}
 }
 
+// UseCgroup 按 cgroup 的内存用量和上限计算占用，未设置上限或上限超过物理内存时以物理内存为上限
+func (m *MemoryMonitor) UseCgroup(g *cgroup.Group) {
+	m.group = g
+}
+
 func (m *MemoryMonitor) GetUsage() (float64, error) {
 	v, err := mem.VirtualMemory()
 	if err != nil {
@@ -27,6 +35,13 @@ func (m *MemoryMonitor) GetUsage() (float64, error) {
 	}
 
 	usage := v.UsedPercent
+	if m.group != nil {
+		used, err := m.group.MemoryUsage()
+		if err != nil {
+			return 0, err
+		}
+		usage = float64(used) / float64(m.limit(v.Total)) * 100
+	}
 
 	m.mu.Lock()
 	m.lastUsage = usage
@@ -46,12 +61,24 @@ func (m *MemoryMonitor) GetMemoryInfo() (*mem.VirtualMemoryStat, error) {
 	return mem.VirtualMemory()
 }
 
+// GetTotalMemory 返回占用率 100% 对应的内存大小，使用 cgroup 时为 cgroup 的内存上限
 func (m *MemoryMonitor) GetTotalMemory() (uint64, error) {
 	v, err := mem.VirtualMemory()
 	if err != nil {
 		return 0, err
 	}
-	return v.Total, nil
+	return m.limit(v.Total), nil
+}
+
+// limit 返回 cgroup 内存上限与物理内存中较小的一个
+func (m *MemoryMonitor) limit(total uint64) uint64 {
+	if m.group == nil {
+		return total
+	}
+	if limit, ok := m.group.MemoryLimit(); ok && limit < total {
+		return limit
+	}
+	return total
 }
 
 func (m *MemoryMonitor) GetUsedMemory() (uint64, error) {
@@ -0,0 +1,111 @@
+package monitor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// NetworkMonitor 统计网卡发送带宽（Mbps）
+// 只统计发送方向，与网络工作器产生的流量方向一致
+type NetworkMonitor struct {
+	mu         sync.RWMutex
+	iface      string
+	lastUsage  float64
+	lastBytes  uint64
+	hasLast    bool
+	updateTime time.Time
+}
+
+// NewNetworkMonitor 创建网络监控，iface 为空时统计除回环网卡外的所有网卡
+func NewNetworkMonitor(iface string) *NetworkMonitor {
+	return &NetworkMonitor{
+		iface:      iface,
+		updateTime: time.Now(),
+	}
+}
+
+func (m *NetworkMonitor) GetUsage() (float64, error) {
+	sent, err := m.readBytesSent()
+	if err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	usage := 0.0
+	if m.hasLast && sent >= m.lastBytes {
+		elapsed := now.Sub(m.updateTime).Seconds()
+		if elapsed > 0 {
+			usage = float64(sent-m.lastBytes) * 8 / elapsed / 1000 / 1000
+		}
+	}
+
+	m.lastBytes = sent
+	m.hasLast = true
+	m.lastUsage = usage
+	m.updateTime = now
+
+	return usage, nil
+}
+
+func (m *NetworkMonitor) GetCachedUsage() float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastUsage
+}
+
+func (m *NetworkMonitor) readBytesSent() (uint64, error) {
+	counters, err := net.IOCounters(true)
+	if err != nil {
+		return 0, err
+	}
+
+	// 回环流量不离开本机，云厂商的闲置检测看不到，默认不计入
+	var loopback map[string]bool
+	if m.iface == "" {
+		loopback = loopbackInterfaces()
+	}
+
+	total := uint64(0)
+	found := false
+	for _, c := range counters {
+		if m.iface != "" && c.Name != m.iface {
+			continue
+		}
+		if loopback[c.Name] {
+			continue
+		}
+		total += c.BytesSent
+		found = true
+	}
+
+	if m.iface != "" && !found {
+		return 0, fmt.Errorf("未找到网卡 %s", m.iface)
+	}
+
+	return total, nil
+}
+
+// loopbackInterfaces 返回回环网卡名称，无法读取网卡标志时按常见名称判断
+func loopbackInterfaces() map[string]bool {
+	names := map[string]bool{"lo": true, "lo0": true}
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return names
+	}
+	for _, iface := range ifaces {
+		for _, flag := range iface.Flags {
+			if flag == "loopback" {
+				names[iface.Name] = true
+				break
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,6 @@
+package monitor
+
+// Source 资源占用率来源，新的资源类型实现此接口即可接入控制循环
+type Source interface {
+	GetUsage() (float64, error)
+}
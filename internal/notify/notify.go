@@ -9,52 +9,40 @@ import (
 )
 
 type Notifier struct {
-	enabled         bool
-	cooldown        int
-	lastNotify      time.Time
-	lastCPUNotify   time.Time
-	lastMemNotify   time.Time
-	mu              sync.Mutex
+	enabled        bool
+	cooldown       int
+	lastNotify     time.Time
+	lastWorkNotify map[string]time.Time // 按资源分别计算冷却时间
+	mu             sync.Mutex
 }
 
 func NewNotifier(enabled bool, cooldown int) *Notifier {
 	now := time.Now().Add(-time.Duration(cooldown) * time.Second)
 	return &Notifier{
-		enabled:       enabled,
-		cooldown:      cooldown,
-		lastNotify:    now,
-		lastCPUNotify: now,
-		lastMemNotify: now,
+		enabled:        enabled,
+		cooldown:       cooldown,
+		lastNotify:     now,
+		lastWorkNotify: make(map[string]time.Time),
 	}
 }
 
-// ============ CPU 相关通知 ============
-
-func (n *Notifier) NotifyCPUWorkStart(threshold int) {
-	if !n.enabled {
-		return
-	}
-
-	n.mu.Lock()
-	defer n.mu.Unlock()
-
-	if time.Since(n.lastCPUNotify) < time.Duration(n.cooldown)*time.Second {
-		return
-	}
+// ============ 工作器相关通知 ============
 
-	title := "Resource Monitor - CPU计算开始"
-	message := fmt.Sprintf("其他程序CPU占用低于阈值 %d%%\n开始CPU负载调整计算", threshold)
-	
-	err := beeep.Notify(title, message, "")
-	if err != nil {
-		// 忽略通知错误
-		return
-	}
+// NotifyWorkStart 通知某类资源的负载计算开始，label 为资源显示名，例如 "CPU"、"内存"
+func (n *Notifier) NotifyWorkStart(label string, threshold int, unit string) {
+	title := fmt.Sprintf("Resource Monitor - %s计算开始", label)
+	message := fmt.Sprintf("其他程序%s占用低于阈值 %d%s\n开始%s负载调整计算", label, threshold, unit, label)
+	n.notifyWork(label, title, message)
+}
 
-	n.lastCPUNotify = time.Now()
+// NotifyWorkStop 通知某类资源的负载计算停止
+func (n *Notifier) NotifyWorkStop(label string) {
+	title := fmt.Sprintf("Resource Monitor - %s计算停止", label)
+	message := fmt.Sprintf("其他程序%s占用达到阈值\n已停止%s负载调整计算", label, label)
+	n.notifyWork(label, title, message)
 }
 
-func (n *Notifier) NotifyCPUWorkStop() {
+func (n *Notifier) notifyWork(label, title, message string) {
 	if !n.enabled {
 		return
 	}
@@ -62,70 +50,37 @@ func (n *Notifier) NotifyCPUWorkStop() {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 
-	if time.Since(n.lastCPUNotify) < time.Duration(n.cooldown)*time.Second {
+	if time.Since(n.lastWorkNotify[label]) < time.Duration(n.cooldown)*time.Second {
 		return
 	}
 
-	title := "Resource Monitor - CPU计算停止"
-	message := "其他程序CPU占用达到阈值\n已停止CPU负载调整计算"
-	
 	err := beeep.Notify(title, message, "")
 	if err != nil {
 		// 忽略通知错误
 		return
 	}
 
-	n.lastCPUNotify = time.Now()
+	n.lastWorkNotify[label] = time.Now()
 }
 
-// ============ 内存 相关通知 ============
-
-func (n *Notifier) NotifyMemWorkStart(threshold int) {
-	if !n.enabled {
-		return
-	}
+// ============ CPU 相关通知 ============
 
-	n.mu.Lock()
-	defer n.mu.Unlock()
+func (n *Notifier) NotifyCPUWorkStart(threshold int) {
+	n.NotifyWorkStart("CPU", threshold, "%")
+}
 
-	if time.Since(n.lastMemNotify) < time.Duration(n.cooldown)*time.Second {
-		return
-	}
+func (n *Notifier) NotifyCPUWorkStop() {
+	n.NotifyWorkStop("CPU")
+}
 
-	title := "Resource Monitor - 内存计算开始"
-	message := fmt.Sprintf("其他程序内存占用低于阈值 %d%%\n开始内存负载调整计算", threshold)
-	
-	err := beeep.Notify(title, message, "")
-	if err != nil {
-		// 忽略通知错误
-		return
-	}
+// ============ 内存 相关通知 ============
 
-	n.lastMemNotify = time.Now()
+func (n *Notifier) NotifyMemWorkStart(threshold int) {
+	n.NotifyWorkStart("内存", threshold, "%")
 }
 
 func (n *Notifier) NotifyMemWorkStop() {
-	if !n.enabled {
-		return
-	}
-
-	n.mu.Lock()
-	defer n.mu.Unlock()
-
-	if time.Since(n.lastMemNotify) < time.Duration(n.cooldown)*time.Second {
-		return
-	}
-
-	title := "Resource Monitor - 内存计算停止"
-	message := "其他程序内存占用达到阈值\n已停止内存负载调整计算"
-	
-	err := beeep.Notify(title, message, "")
-	if err != nil {
-		// 忽略通知错误
-		return
-	}
-
-	n.lastMemNotify = time.Now()
+	n.NotifyWorkStop("内存")
 }
 
 // ============ 通用通知 ============
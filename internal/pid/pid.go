@@ -0,0 +1,120 @@
+package pid
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Controller 带抗积分饱和的 PID 控制器
+// 积分项以输出量纲累积，修改增益时不会造成输出跳变
+type Controller struct {
+	mu sync.Mutex
+
+	kp float64
+	ki float64
+	kd float64
+
+	outMin float64
+	outMax float64
+
+	integral     float64
+	lastMeasured float64
+	hasLast      bool
+	lastOutput   float64
+}
+
+func New(kp, ki, kd, outMin, outMax float64) *Controller {
+	return &Controller{
+		kp:     kp,
+		ki:     ki,
+		kd:     kd,
+		outMin: outMin,
+		outMax: outMax,
+	}
+}
+
+// Update 根据设定值和测量值计算新的输出，dt 为距上次计算的时间
+func (c *Controller) Update(setpoint, measured float64, dt time.Duration) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	secs := dt.Seconds()
+	if secs <= 0 {
+		return c.lastOutput
+	}
+
+	err := setpoint - measured
+
+	// 微分作用于测量值，避免设定值突变带来的冲击
+	derivative := 0.0
+	if c.hasLast {
+		derivative = -c.kd * (measured - c.lastMeasured) / secs
+	}
+	c.lastMeasured = measured
+	c.hasLast = true
+
+	proportional := c.kp * err
+	integral := c.integral + c.ki*err*secs
+
+	// 抗积分饱和：输出越限且误差继续推向饱和方向时，积分只累积到恰好使输出贴住限幅
+	// 不直接冻结积分，否则比例项回落后输出会停在限幅以内，留下稳态误差
+	output := proportional + integral + derivative
+	if output > c.outMax && err > 0 {
+		integral = math.Max(c.integral, c.outMax-proportional-derivative)
+		output = proportional + integral + derivative
+	} else if output < c.outMin && err < 0 {
+		integral = math.Min(c.integral, c.outMin-proportional-derivative)
+		output = proportional + integral + derivative
+	}
+	c.integral = clamp(integral, c.outMin, c.outMax)
+
+	output = clamp(output, c.outMin, c.outMax)
+	c.lastOutput = output
+	return output
+}
+
+// Reset 清除历史状态，并以 output 作为积分初值实现无扰切换
+func (c *Controller) Reset(output float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	output = clamp(output, c.outMin, c.outMax)
+	c.integral = output
+	c.lastOutput = output
+	c.lastMeasured = 0
+	c.hasLast = false
+}
+
+// SetGains 更新控制器增益
+func (c *Controller) SetGains(kp, ki, kd float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.kp = kp
+	c.ki = ki
+	c.kd = kd
+}
+
+// GetGains 获取控制器增益
+func (c *Controller) GetGains() (kp, ki, kd float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.kp, c.ki, c.kd
+}
+
+// GetOutput 获取最近一次的输出
+func (c *Controller) GetOutput() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastOutput
+}
+
+func clamp(v, min, max float64) float64 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
@@ -0,0 +1,223 @@
+package pid
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+// scenario 描述一次确定性的闭环仿真
+// 每个周期的设定值为 Threshold - Other[k]，与主循环中 "阈值 - 其他程序占用" 的计算方式一致
+type scenario struct {
+	Dt        time.Duration
+	Threshold float64
+	Other     []float64 // 合成的其他程序占用序列，长度即仿真周期数
+	MaxTarget float64   // 设定值上限（CPU 为 100，内存为 80）
+	Gain      float64   // 被控对象增益：测量值 = Gain * 输出
+	Lag       int       // 测量滞后的周期数
+	Band      float64   // 判定稳定的误差带（绝对值）
+	Initial   float64   // 控制器初始输出
+}
+
+// result 仿真结果
+type result struct {
+	Setpoints    []float64
+	Measurements []float64
+	Outputs      []float64
+	Settled      bool
+	SettlingTime time.Duration // 自最后一次设定值变化起进入误差带所需时间
+	Overshoot    float64       // 相对最后一次阶跃幅度的超调百分比
+}
+
+// simulate 使用给定控制器运行仿真，结果只取决于输入，可重复
+func simulate(c *Controller, s scenario) result {
+	n := len(s.Other)
+	res := result{
+		Setpoints:    make([]float64, n),
+		Measurements: make([]float64, n),
+		Outputs:      make([]float64, n),
+	}
+	if n == 0 {
+		return res
+	}
+
+	gain := s.Gain
+	if gain == 0 {
+		gain = 1
+	}
+	maxTarget := s.MaxTarget
+	if maxTarget == 0 {
+		maxTarget = 100
+	}
+
+	c.Reset(s.Initial)
+
+	history := make([]float64, 0, n+s.Lag+1)
+	for i := 0; i <= s.Lag; i++ {
+		history = append(history, s.Initial)
+	}
+
+	for k := 0; k < n; k++ {
+		setpoint := clamp(s.Threshold-s.Other[k], 0, maxTarget)
+		measured := gain * history[len(history)-1-s.Lag]
+
+		output := c.Update(setpoint, measured, s.Dt)
+		history = append(history, output)
+
+		res.Setpoints[k] = setpoint
+		res.Measurements[k] = measured
+		res.Outputs[k] = output
+	}
+
+	res.analyze(s.Dt, s.Band)
+	return res
+}
+
+func (r *result) analyze(dt time.Duration, band float64) {
+	n := len(r.Setpoints)
+
+	lastStep := 0
+	for k := 1; k < n; k++ {
+		if r.Setpoints[k] != r.Setpoints[k-1] {
+			lastStep = k
+		}
+	}
+
+	final := r.Setpoints[n-1]
+	start := r.Measurements[lastStep]
+	step := final - start
+
+	settledAt := -1
+	for k := n - 1; k >= lastStep; k-- {
+		if math.Abs(r.Measurements[k]-final) > band {
+			break
+		}
+		settledAt = k
+	}
+	if settledAt >= 0 {
+		r.Settled = true
+		r.SettlingTime = time.Duration(settledAt-lastStep) * dt
+	}
+
+	if step != 0 {
+		peak := 0.0
+		for k := lastStep; k < n; k++ {
+			over := (r.Measurements[k] - final) / step
+			if over > peak {
+				peak = over
+			}
+		}
+		r.Overshoot = peak * 100
+	}
+}
+
+// verify 检查仿真结果是否满足调节时间和超调量要求
+func (r result) verify(maxSettling time.Duration, maxOvershoot float64) error {
+	if !r.Settled {
+		return fmt.Errorf("未能进入稳定误差带")
+	}
+	if r.SettlingTime > maxSettling {
+		return fmt.Errorf("调节时间 %v 超过上限 %v", r.SettlingTime, maxSettling)
+	}
+	if r.Overshoot > maxOvershoot {
+		return fmt.Errorf("超调量 %.1f%% 超过上限 %.1f%%", r.Overshoot, maxOvershoot)
+	}
+	return nil
+}
+
+// series 按段拼接合成的其他程序占用序列，每段为 {周期数, 占用}
+func series(segments ...[2]float64) []float64 {
+	var out []float64
+	for _, seg := range segments {
+		for i := 0; i < int(seg[0]); i++ {
+			out = append(out, seg[1])
+		}
+	}
+	return out
+}
+
+func TestControllerScenarios(t *testing.T) {
+	dt := 2 * time.Second
+
+	tests := []struct {
+		name         string
+		kp, ki, kd   float64
+		scenario     scenario
+		maxSettling  time.Duration
+		maxOvershoot float64
+	}{
+		{
+			name: "step from idle",
+			kp:   0.2, ki: 0.2,
+			scenario: scenario{
+				Dt:        dt,
+				Threshold: 60,
+				Other:     series([2]float64{60, 0}),
+				Lag:       1,
+				Band:      2,
+			},
+			maxSettling:  20 * time.Second,
+			maxOvershoot: 8,
+		},
+		{
+			name: "load disturbance",
+			kp:   0.2, ki: 0.2,
+			scenario: scenario{
+				Dt:        dt,
+				Threshold: 60,
+				Other:     series([2]float64{40, 10}, [2]float64{60, 40}),
+				Lag:       1,
+				Band:      2,
+				Initial:   50,
+			},
+			maxSettling:  12 * time.Second,
+			maxOvershoot: 8,
+		},
+		{
+			// 被控对象增益 0.5，设定值 80 不可达，输出长期饱和在 100；
+			// 随后设定值降到 20，积分未饱和时应能迅速退出饱和
+			name: "saturation and anti-windup",
+			kp:   0.2, ki: 0.2,
+			scenario: scenario{
+				Dt:        dt,
+				Threshold: 90,
+				Other:     series([2]float64{100, 10}, [2]float64{60, 70}),
+				Gain:      0.5,
+				Lag:       1,
+				Band:      2,
+			},
+			maxSettling:  30 * time.Second,
+			maxOvershoot: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(tt.kp, tt.ki, tt.kd, 0, 100)
+			res := simulate(c, tt.scenario)
+			for k, out := range res.Outputs {
+				if out < 0 || out > 100 {
+					t.Fatalf("周期 %d 输出 %.2f 超出 [0, 100]", k, out)
+				}
+			}
+			if err := res.verify(tt.maxSettling, tt.maxOvershoot); err != nil {
+				t.Fatalf("%v (调节时间 %v, 超调 %.1f%%)", err, res.SettlingTime, res.Overshoot)
+			}
+		})
+	}
+}
+
+func TestControllerSaturates(t *testing.T) {
+	c := New(0.2, 0.2, 0, 0, 100)
+	res := simulate(c, scenario{
+		Dt:        2 * time.Second,
+		Threshold: 90,
+		Other:     series([2]float64{100, 10}),
+		Gain:      0.5,
+		Lag:       1,
+	})
+	if got := res.Outputs[len(res.Outputs)-1]; got != 100 {
+		t.Fatalf("设定值不可达时输出应饱和在 100，实际 %.2f", got)
+	}
+}
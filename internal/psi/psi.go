@@ -0,0 +1,79 @@
+package psi
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Stall 一类停顿的统计，Avg 为最近 10/60/300 秒内任务因资源不足而停顿的时间百分比
+type Stall struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	Total  uint64 // 累计停顿时间（微秒）
+}
+
+// Pressure 资源压力
+// Some 为至少有一个任务停顿的时间，Full 为所有非空闲任务同时停顿的时间
+type Pressure struct {
+	Some Stall
+	Full Stall
+}
+
+// Read 读取 /proc/pressure 下的资源压力，resource 为 cpu、memory 或 io
+// 需要 Linux 4.20 以上且内核启用了 PSI
+func Read(resource string) (Pressure, error) {
+	data, err := os.ReadFile("/proc/pressure/" + resource)
+	if err != nil {
+		return Pressure{}, fmt.Errorf("读取 %s 压力失败: %w", resource, err)
+	}
+	return parse(string(data))
+}
+
+// parse 解析 PSI 文件内容，例如:
+// some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+// full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parse(data string) (Pressure, error) {
+	var p Pressure
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var stall *Stall
+		switch fields[0] {
+		case "some":
+			stall = &p.Some
+		case "full":
+			stall = &p.Full
+		default:
+			continue
+		}
+
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return p, fmt.Errorf("无效的压力数据: %s", line)
+			}
+
+			var err error
+			switch key {
+			case "avg10":
+				stall.Avg10, err = strconv.ParseFloat(value, 64)
+			case "avg60":
+				stall.Avg60, err = strconv.ParseFloat(value, 64)
+			case "avg300":
+				stall.Avg300, err = strconv.ParseFloat(value, 64)
+			case "total":
+				stall.Total, err = strconv.ParseUint(value, 10, 64)
+			}
+			if err != nil {
+				return p, fmt.Errorf("无效的压力数据: %s", line)
+			}
+		}
+	}
+	return p, nil
+}
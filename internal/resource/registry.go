@@ -0,0 +1,90 @@
+package resource
+
+import (
+	"fmt"
+	"sync"
+
+	"MikaBooM/internal/config"
+	"MikaBooM/internal/monitor"
+	"MikaBooM/internal/worker"
+)
+
+const (
+	CPU     = "cpu"
+	Memory  = "memory"
+	Network = "network"
+	Disk    = "disk"
+)
+
+// Resource 一种受控资源：监控来源、负载工作器以及阈值
+type Resource struct {
+	Name      string // 注册键，例如 "cpu"
+	Tag       string // 控制台简称，例如 "MEM"
+	Label     string // 界面显示名，例如 "内存"
+	Unit      string // 占用率与阈值的单位，例如 "%"、"Mbps"
+	Source    monitor.Source
+	Loader    worker.Loader // 为 nil 时只监控不调整
+	MaxTarget float64       // 工作器目标占用上限，0 表示不限制
+	Threshold func(cfg *config.Config) int
+}
+
+// Registry 按名称登记资源，并保持注册顺序
+type Registry struct {
+	mu    sync.RWMutex
+	order []string
+	items map[string]*Resource
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		items: make(map[string]*Resource),
+	}
+}
+
+func (r *Registry) Register(res *Resource) error {
+	if res == nil || res.Name == "" {
+		return fmt.Errorf("资源名称不能为空")
+	}
+	if res.Source == nil {
+		return fmt.Errorf("资源 %s 缺少监控来源", res.Name)
+	}
+	if res.Threshold == nil {
+		return fmt.Errorf("资源 %s 缺少阈值配置", res.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.items[res.Name]; exists {
+		return fmt.Errorf("资源 %s 已注册", res.Name)
+	}
+
+	r.items[res.Name] = res
+	r.order = append(r.order, res.Name)
+	return nil
+}
+
+func (r *Registry) Get(name string) (*Resource, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	res, ok := r.items[name]
+	return res, ok
+}
+
+// All 按注册顺序返回所有资源
+func (r *Registry) All() []*Resource {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*Resource, 0, len(r.order))
+	for _, name := range r.order {
+		list = append(list, r.items[name])
+	}
+	return list
+}
+
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.order...)
+}
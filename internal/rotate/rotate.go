@@ -0,0 +1,154 @@
+package rotate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Writer 只追加写入的文件，超过大小上限时轮转
+// 轮转后 path.1 为最近的旧文件，path.N 为最早的旧文件，超过 maxFiles 的旧文件被删除
+type Writer struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+	maxAge   time.Duration
+	file     *os.File
+	size     int64
+}
+
+// Open 打开或创建 path，maxSize <= 0 时不轮转
+func Open(path string, maxSize int64, maxFiles int) (*Writer, error) {
+	w := &Writer{
+		path:     path,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Writer) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("打开文件失败: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("读取文件信息失败: %w", err)
+	}
+
+	w.file = f
+	w.size = info.Size()
+	return nil
+}
+
+// SetMaxAge 设置轮转文件的保留时长，超过的旧文件在轮转时删除，0 为不按时长删除
+func (w *Writer) SetMaxAge(maxAge time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.maxAge = maxAge
+	w.removeExpired()
+}
+
+// removeExpired 删除修改时间早于保留时长的轮转文件
+func (w *Writer) removeExpired() {
+	if w.maxAge <= 0 {
+		return
+	}
+
+	cutoff := time.Now().Add(-w.maxAge)
+	for i := 1; i <= w.maxFiles; i++ {
+		name := backupName(w.path, i)
+		if info, err := os.Stat(name); err == nil && info.ModTime().Before(cutoff) {
+			os.Remove(name)
+		}
+	}
+}
+
+// Write 写入 p，写入后会超过大小上限时先轮转；p 应为完整的一条或多条记录
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("关闭文件失败: %w", err)
+	}
+	w.file = nil
+
+	if w.maxFiles <= 0 {
+		os.Remove(w.path)
+	} else {
+		os.Remove(backupName(w.path, w.maxFiles))
+		for i := w.maxFiles - 1; i >= 1; i-- {
+			os.Rename(backupName(w.path, i), backupName(w.path, i+1))
+		}
+		if err := os.Rename(w.path, backupName(w.path, 1)); err != nil {
+			return fmt.Errorf("轮转文件失败: %w", err)
+		}
+		w.removeExpired()
+	}
+
+	return w.open()
+}
+
+// Close 关闭文件
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// Files 返回 path 及其轮转文件中存在的部分，按从旧到新排列
+func Files(path string) []string {
+	var files []string
+	for i := 1; ; i++ {
+		name := backupName(path, i)
+		if _, err := os.Stat(name); err != nil {
+			break
+		}
+		files = append([]string{name}, files...)
+	}
+	if _, err := os.Stat(path); err == nil {
+		files = append(files, path)
+	}
+	return files
+}
+
+func backupName(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
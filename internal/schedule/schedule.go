@@ -0,0 +1,138 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+
+	"MikaBooM/internal/config"
+	"MikaBooM/internal/controller"
+)
+
+// minutesPerDay 一天的分钟数
+const minutesPerDay = 24 * 60
+
+// Rule 一条计划规则
+type Rule struct {
+	Name       string
+	Off        bool
+	days       map[time.Weekday]bool // nil 表示每天
+	start      int                   // 自零点起的分钟数
+	end        int
+	thresholds map[string]int
+}
+
+// Matches 判断规则在 now 时刻是否生效
+// 跨越午夜的时间段中，零点之后的部分归属于前一天，例如周五 22:00-06:00 覆盖周六凌晨
+func (r *Rule) Matches(now time.Time) bool {
+	minute := now.Hour()*60 + now.Minute()
+	day := now.Weekday()
+
+	switch {
+	case r.start == r.end:
+		return r.onDay(day)
+	case r.start < r.end:
+		return minute >= r.start && minute < r.end && r.onDay(day)
+	case minute >= r.start:
+		return r.onDay(day)
+	case minute < r.end:
+		return r.onDay((day + 6) % 7)
+	}
+	return false
+}
+
+func (r *Rule) onDay(day time.Weekday) bool {
+	return r.days == nil || r.days[day]
+}
+
+// Threshold 返回规则下资源的阈值，未覆盖的资源沿用传入的阈值
+func (r *Rule) Threshold(name string, threshold int) int {
+	if r.Off {
+		return 0
+	}
+	if value, ok := r.thresholds[name]; ok {
+		return value
+	}
+	return threshold
+}
+
+// Window 返回规则的时间段描述
+func (r *Rule) Window() string {
+	if r.start == r.end {
+		return "全天"
+	}
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", r.start/60, r.start%60, r.end/60, r.end%60)
+}
+
+// Schedule 按时间段覆盖阈值，实现 controller.Policy
+// 规则按配置顺序匹配，第一条命中的规则生效，没有规则命中时使用全局阈值
+type Schedule struct {
+	rules []*Rule
+}
+
+// New 根据配置创建计划，配置应已通过 config.ValidateConfig 校验
+func New(cfgs []config.ScheduleConfig) (*Schedule, error) {
+	s := &Schedule{}
+	for i, c := range cfgs {
+		name := c.Name
+		if name == "" {
+			name = fmt.Sprintf("规则%d", i+1)
+		}
+
+		days, err := c.Weekdays()
+		if err != nil {
+			return nil, fmt.Errorf("计划 %s: %w", name, err)
+		}
+		start, end, err := c.Window()
+		if err != nil {
+			return nil, fmt.Errorf("计划 %s: %w", name, err)
+		}
+
+		s.rules = append(s.rules, &Rule{
+			Name:       name,
+			Off:        c.Off,
+			days:       days,
+			start:      start % minutesPerDay,
+			end:        end % minutesPerDay,
+			thresholds: c.Thresholds(),
+		})
+	}
+	return s, nil
+}
+
+// Rules 返回所有规则
+func (s *Schedule) Rules() []*Rule {
+	return s.rules
+}
+
+// Active 返回 now 时刻生效的规则，没有时返回 nil
+func (s *Schedule) Active(now time.Time) *Rule {
+	for _, r := range s.rules {
+		if r.Matches(now) {
+			return r
+		}
+	}
+	return nil
+}
+
+// Threshold 实现 controller.Policy
+func (s *Schedule) Threshold(name string, threshold int, otherUsage float64, now time.Time) int {
+	if r := s.Active(now); r != nil {
+		return r.Threshold(name, threshold)
+	}
+	return threshold
+}
+
+// Observe 实现 controller.Policy，计划不需要记录历史
+func (s *Schedule) Observe(state controller.ResourceState, now time.Time) {}
+
+// Describe 实现 controller.Describer
+func (s *Schedule) Describe(now time.Time) string {
+	r := s.Active(now)
+	if r == nil {
+		return "计划: 默认"
+	}
+	if r.Off {
+		return fmt.Sprintf("计划: %s (停止)", r.Name)
+	}
+	return fmt.Sprintf("计划: %s", r.Name)
+}
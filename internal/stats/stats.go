@@ -0,0 +1,22 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// NearestRank 最近秩法计算百分位，p 取 0-1，不修改 values
+func NearestRank(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,32 @@
+package stats
+
+import "testing"
+
+func TestNearestRank(t *testing.T) {
+	values := []float64{15, 20, 35, 40, 50}
+
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{0, 15},
+		{0.05, 15},
+		{0.30, 20},
+		{0.40, 20},
+		{0.50, 35},
+		{0.95, 50},
+		{1, 50},
+	}
+	for _, tt := range tests {
+		if got := NearestRank(values, tt.p); got != tt.want {
+			t.Errorf("NearestRank(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+
+	if values[0] != 15 || values[4] != 50 {
+		t.Fatalf("input was modified: %v", values)
+	}
+	if got := NearestRank(nil, 0.95); got != 0 {
+		t.Fatalf("NearestRank(nil) = %v, want 0", got)
+	}
+}
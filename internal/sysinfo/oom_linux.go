@@ -0,0 +1,18 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// SetOOMScoreAdj 设置当前进程的 oom_score_adj，取值 -1000 到 1000，越大越先被 OOM killer 终止
+// 提高分数不需要特权，降低到启动时的值以下需要 CAP_SYS_RESOURCE
+func SetOOMScoreAdj(score int) error {
+	if err := os.WriteFile("/proc/self/oom_score_adj", []byte(strconv.Itoa(score)), 0644); err != nil {
+		return fmt.Errorf("设置 oom_score_adj 失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package sysinfo
+
+// SetOOMScoreAdj 其他平台没有 OOM 评分，忽略
+func SetOOMScoreAdj(score int) error {
+	return nil
+}
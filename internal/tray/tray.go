@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"log"
 	"runtime"
+	"sync"
 	"time"
 
 	"MikaBooM/internal/autostart"
 	"MikaBooM/internal/config"
-	"MikaBooM/internal/monitor"
+	"MikaBooM/internal/controller"
+	"MikaBooM/internal/resource"
 	"MikaBooM/internal/worker"
 
 	"github.com/getlantern/systray"
@@ -24,21 +26,24 @@ var iconLinux []byte
 //go:embed assets/icon_macos.png
 var iconMacOS []byte
 
+// resourceMenu 单个资源对应的菜单项
+type resourceMenu struct {
+	res    *resource.Resource
+	usage  *systray.MenuItem
+	worker *systray.MenuItem
+}
+
 var (
-	cpuMonitor *monitor.CPUMonitor
-	memMonitor *monitor.MemoryMonitor
-	cpuWorker  *worker.CPUWorker
-	memWorker  *worker.MemoryWorker
-
-	mCPU        *systray.MenuItem
-	mMEM        *systray.MenuItem
-	mCPUWorker  *systray.MenuItem
-	mMemWorker  *systray.MenuItem
+	ctrl  *controller.Controller
+	menus []*resourceMenu
+
 	mAutostart  *systray.MenuItem
+	mStatus     *systray.MenuItem
 	mQuit       *systray.MenuItem
 	
 	stopUpdateLoop chan struct{}
 	quitChan       chan struct{}
+	quitOnce       sync.Once
 )
 
 // GetQuitChannel 返回退出信号 channel
@@ -49,17 +54,20 @@ func GetQuitChannel() <-chan struct{} {
 	return quitChan
 }
 
+// RequestQuit 请求退出程序，与托盘“退出”菜单走同一路径，可安全地多次调用
+func RequestQuit() {
+	quitOnce.Do(func() {
+		if quitChan != nil {
+			close(quitChan)
+		}
+	})
+}
+
 func Start(
 	config *config.Config,
-	cpu *monitor.CPUMonitor,
-	mem *monitor.MemoryMonitor,
-	cpuW *worker.CPUWorker,
-	memW *worker.MemoryWorker,
+	c *controller.Controller,
 ) {
-	cpuMonitor = cpu
-	memMonitor = mem
-	cpuWorker = cpuW
-	memWorker = memW
+	ctrl = c
 
 	systray.Run(onReady, onExit)
 }
@@ -76,22 +84,23 @@ func onReady() {
 		log.Println("⚠ 托盘图标加载失败，使用系统默认图标")
 	}
 
-	mCPU = systray.AddMenuItem("CPU: 0.0%", "CPU使用率")
-	mCPU.Disable()
+	resources := ctrl.Registry().All()
+	menus = make([]*resourceMenu, 0, len(resources))
 
-	mMEM = systray.AddMenuItem("MEM: 0.0%", "内存使用率")
-	mMEM.Disable()
+	for _, res := range resources {
+		item := systray.AddMenuItem(fmt.Sprintf("%s: 0.0%s", res.Tag, res.Unit), res.Label+"使用率")
+		item.Disable()
+		menus = append(menus, &resourceMenu{res: res, usage: item})
+	}
 
 	systray.AddSeparator()
 
-	if cpuWorker != nil {
-		mCPUWorker = systray.AddMenuItem("CPU计算: 就绪", "CPU工作器状态")
-		mCPUWorker.Disable()
-	}
-
-	if memWorker != nil {
-		mMemWorker = systray.AddMenuItem("内存计算: 就绪", "内存工作器状态")
-		mMemWorker.Disable()
+	for _, m := range menus {
+		if m.res.Loader == nil {
+			continue
+		}
+		m.worker = systray.AddMenuItem(m.res.Label+"计算: 就绪", m.res.Label+"工作器状态")
+		m.worker.Disable()
 	}
 
 	systray.AddSeparator()
@@ -101,7 +110,7 @@ func onReady() {
 
 	systray.AddSeparator()
 
-	mStatus := systray.AddMenuItem("状态: 监控中", "当前状态")
+	mStatus = systray.AddMenuItem("状态: 监控中", "当前状态")
 	mStatus.Disable()
 
 	systray.AddSeparator()
@@ -125,9 +134,7 @@ func handleMenuEvents() {
 			toggleAutostart()
 		case <-mQuit.ClickedCh:
 			// 通知主程序退出
-			if quitChan != nil {
-				close(quitChan)
-			}
+			RequestQuit()
 			systray.Quit()
 			return
 		}
@@ -194,11 +201,8 @@ func onExit() {
 	time.Sleep(100 * time.Millisecond)
 	
 	// 停止 worker
-	if cpuWorker != nil {
-		cpuWorker.Stop()
-	}
-	if memWorker != nil {
-		memWorker.Stop()
+	if ctrl != nil {
+		ctrl.Shutdown()
 	}
 }
 
@@ -211,54 +215,54 @@ func updateLoop() {
 		case <-stopUpdateLoop:
 			return
 		case <-ticker.C:
-			cpuUsage, err := cpuMonitor.GetUsage()
-			if err != nil {
-				log.Printf("获取CPU使用率失败: %v", err)
+			// 读取主循环最近一次的决策结果，不再单独采样
+			snap, ok := ctrl.Latest()
+			if !ok {
 				continue
 			}
 
-			memUsage, err := memMonitor.GetUsage()
-			if err != nil {
-				log.Printf("获取内存使用率失败: %v", err)
-				continue
-			}
+			tooltip := "MikaBooM - Miku Edition\n"
+			workerTips := ""
+			for i, m := range menus {
+				state, ok := snap.Get(m.res.Name)
+				if !ok {
+					continue
+				}
 
-			if mCPU != nil {
-				mCPU.SetTitle(fmt.Sprintf("CPU: %.1f%%", cpuUsage))
-			}
-			if mMEM != nil {
-				mMEM.SetTitle(fmt.Sprintf("MEM: %.1f%%", memUsage))
-			}
+				m.usage.SetTitle(fmt.Sprintf("%s: %.1f%s", m.res.Tag, state.Usage, m.res.Unit))
+				if i > 0 {
+					tooltip += " | "
+				}
+				tooltip += fmt.Sprintf("%s: %.1f%s", m.res.Tag, state.Usage, m.res.Unit)
 
-			if cpuWorker != nil && mCPUWorker != nil {
-				if cpuWorker.IsRunning() {
-					intensity := cpuWorker.GetIntensity()
-					workerUsage := cpuWorker.GetUsage()
-					mCPUWorker.SetTitle(fmt.Sprintf("CPU计算: 运行中 (强度:%d%%, 占用:%.1f%%)", intensity, workerUsage))
-				} else {
-					mCPUWorker.SetTitle("CPU计算: 已停止")
+				if m.worker == nil {
+					continue
 				}
-			}
 
-			if memWorker != nil && mMemWorker != nil {
-				if memWorker.IsRunning() {
-					allocMB := memWorker.GetAllocatedSize() / 1024 / 1024
-					targetMB := memWorker.GetTargetSize() / 1024 / 1024
-					workerUsage := memWorker.GetUsage()
-					mMemWorker.SetTitle(fmt.Sprintf("内存计算: 运行中 (已分配:%dMB, 目标:%dMB, 占用:%.1f%%)", allocMB, targetMB, workerUsage))
+				if state.Paused {
+					m.worker.SetTitle(m.res.Label + "计算: 已暂停")
+				} else if m.res.Loader.IsRunning() {
+					status := "ON"
+					title := fmt.Sprintf("%s计算: 运行中 (占用:%.1f%s)", m.res.Label, state.WorkerUsage, m.res.Unit)
+					if reporter, ok := m.res.Loader.(worker.Reporter); ok {
+						status = fmt.Sprintf("ON (%s)", reporter.Status())
+						title = fmt.Sprintf("%s计算: 运行中 (%s, 占用:%.1f%s)", m.res.Label, reporter.Status(), state.WorkerUsage, m.res.Unit)
+					}
+					m.worker.SetTitle(title)
+					workerTips += fmt.Sprintf("\n%s工作器: %s", m.res.Label, status)
 				} else {
-					mMemWorker.SetTitle("内存计算: 已停止")
+					m.worker.SetTitle(m.res.Label + "计算: 已停止")
 				}
 			}
+			tooltip += workerTips
 
-			tooltip := fmt.Sprintf("MikaBooM - Miku Edition\nCPU: %.1f%% | MEM: %.1f%%", cpuUsage, memUsage)
-			if cpuWorker != nil && cpuWorker.IsRunning() {
-				tooltip += fmt.Sprintf("\nCPU工作器: ON (强度:%d%%)", cpuWorker.GetIntensity())
-			}
-			if memWorker != nil && memWorker.IsRunning() {
-				allocMB := memWorker.GetAllocatedSize() / 1024 / 1024
-				tooltip += fmt.Sprintf("\n内存工作器: ON (已分配:%dMB)", allocMB)
+			// 策略状态（如当前生效的计划）显示在状态项中
+			status := "状态: 监控中"
+			for _, note := range snap.Notes {
+				status += " | " + note
+				tooltip += "\n" + note
 			}
+			mStatus.SetTitle(status)
 			systray.SetTooltip(tooltip)
 		}
 	}
@@ -0,0 +1,474 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"MikaBooM/internal/controller"
+	"MikaBooM/internal/resource"
+	"MikaBooM/internal/version"
+	"MikaBooM/internal/worker"
+)
+
+const (
+	defaultWidth    = 80
+	defaultHeight   = 24
+	historySize     = 240             // 每个资源保留的历史样本数
+	thresholdStep   = 5               // +/- 调整阈值的步长
+	perCoreInterval = 2 * time.Second // 每核心占用的刷新间隔
+)
+
+// ANSI 控制序列
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiDim       = "\x1b[90m"
+	ansiRed       = "\x1b[31m"
+	ansiGreen     = "\x1b[32m"
+	ansiYellow    = "\x1b[33m"
+	ansiCyan      = "\x1b[36m"
+	ansiMagenta   = "\x1b[35m"
+	ansiEnter     = "\x1b[?1049h\x1b[?25l\x1b[?7l" // 备用屏幕、隐藏光标、关闭自动换行
+	ansiLeave     = "\x1b[?7h\x1b[?25h\x1b[?1049l"
+	ansiHome      = "\x1b[H"
+	ansiClearLine = "\x1b[K"
+	ansiClearDown = "\x1b[J"
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Dashboard 全屏终端仪表盘，每次 Update 重绘整个屏幕
+type Dashboard struct {
+	ctrl    *controller.Controller
+	perCore func() ([]float64, error)
+	quit    func()
+	out     io.Writer
+
+	mu       sync.Mutex
+	snap     controller.Snapshot
+	history  map[string][]float64
+	cores    []float64
+	selected int
+	message  string
+
+	restore  func()
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// New 创建仪表盘，perCore 返回每个核心的占用（可为 nil），quit 在按下 q 时调用
+func New(ctrl *controller.Controller, perCore func() ([]float64, error), quit func()) *Dashboard {
+	return &Dashboard{
+		ctrl:    ctrl,
+		perCore: perCore,
+		quit:    quit,
+		out:     os.Stdout,
+		history: make(map[string][]float64),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start 切换到全屏模式并开始读取按键
+func (d *Dashboard) Start() error {
+	restore, err := makeRaw()
+	if err != nil {
+		return fmt.Errorf("无法进入终端仪表盘模式: %w", err)
+	}
+	d.restore = restore
+
+	fmt.Fprint(d.out, ansiEnter)
+	go d.readKeys()
+	if d.perCore != nil {
+		go d.sampleCores()
+	}
+	d.redraw()
+	return nil
+}
+
+// Stop 恢复终端状态
+func (d *Dashboard) Stop() {
+	d.stopOnce.Do(func() {
+		close(d.done)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		fmt.Fprint(d.out, ansiLeave)
+		if d.restore != nil {
+			d.restore()
+		}
+	})
+}
+
+// Update 接收一轮决策结果并重绘
+func (d *Dashboard) Update(snap controller.Snapshot) {
+	d.mu.Lock()
+	d.snap = snap
+	for _, s := range snap.Resources {
+		h := append(d.history[s.Name], s.Usage)
+		if len(h) > historySize {
+			h = h[len(h)-historySize:]
+		}
+		d.history[s.Name] = h
+	}
+	for _, e := range snap.Events {
+		action := "停止"
+		if e.Started {
+			action = "开始"
+		}
+		d.message = fmt.Sprintf("%s [%s] 其他程序占用 %.1f，阈值 %d，%s计算", snap.Time.Format("15:04:05"), e.Resource, e.OtherUsage, e.Threshold, action)
+	}
+	d.mu.Unlock()
+
+	d.redraw()
+}
+
+// Notice 在底部状态行显示一条消息
+func (d *Dashboard) Notice(format string, args ...interface{}) {
+	d.mu.Lock()
+	d.message = fmt.Sprintf(format, args...)
+	d.mu.Unlock()
+	d.redraw()
+}
+
+func (d *Dashboard) sampleCores() {
+	ticker := time.NewTicker(perCoreInterval)
+	defer ticker.Stop()
+
+	for {
+		// GetPerCoreUsage 会阻塞一个采样周期，在单独的 goroutine 中执行
+		cores, err := d.perCore()
+		if err == nil {
+			d.mu.Lock()
+			d.cores = cores
+			d.mu.Unlock()
+			d.redraw()
+		}
+
+		select {
+		case <-d.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dashboard) readKeys() {
+	buf := make([]byte, 16)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return
+		}
+		select {
+		case <-d.done:
+			return
+		default:
+		}
+
+		key := string(buf[:n])
+		switch key {
+		case "q", "Q":
+			d.Notice("正在退出...")
+			if d.quit != nil {
+				d.quit()
+			}
+			return
+		case "\x1b[A", "k":
+			d.moveSelection(-1)
+		case "\x1b[B", "j", "\t":
+			d.moveSelection(1)
+		case "p", " ":
+			d.togglePause()
+		case "+", "=":
+			d.adjustThreshold(thresholdStep)
+		case "-", "_":
+			d.adjustThreshold(-thresholdStep)
+		case "r":
+			d.resetThreshold()
+		}
+	}
+}
+
+func (d *Dashboard) selectedResource() *resource.Resource {
+	resources := d.ctrl.Registry().All()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.selected >= len(resources) {
+		d.selected = 0
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+	return resources[d.selected]
+}
+
+func (d *Dashboard) moveSelection(delta int) {
+	count := len(d.ctrl.Registry().All())
+	if count == 0 {
+		return
+	}
+
+	d.mu.Lock()
+	d.selected = (d.selected + delta + count) % count
+	d.mu.Unlock()
+	d.redraw()
+}
+
+func (d *Dashboard) togglePause() {
+	res := d.selectedResource()
+	if res == nil {
+		return
+	}
+	if res.Loader == nil {
+		d.Notice("%s 没有工作器", res.Label)
+		return
+	}
+
+	if d.ctrl.IsPaused(res.Name) {
+		d.ctrl.Resume(res.Name)
+		d.Notice("已恢复%s工作器", res.Label)
+	} else {
+		d.ctrl.Pause(res.Name)
+		d.Notice("已暂停%s工作器", res.Label)
+	}
+}
+
+func (d *Dashboard) adjustThreshold(delta int) {
+	res := d.selectedResource()
+	if res == nil {
+		return
+	}
+
+	value, _ := d.ctrl.BaseThreshold(res.Name)
+	value += delta
+	if value < 0 {
+		value = 0
+	}
+	if res.Unit == "%" && value > 100 {
+		value = 100
+	}
+
+	if err := d.ctrl.SetThreshold(res.Name, value); err != nil {
+		d.Notice("%v", err)
+		return
+	}
+	d.Notice("%s阈值已设置为 %d%s，下一轮生效", res.Label, value, res.Unit)
+}
+
+func (d *Dashboard) resetThreshold() {
+	res := d.selectedResource()
+	if res == nil {
+		return
+	}
+
+	d.ctrl.ClearThreshold(res.Name)
+	value, _ := d.ctrl.BaseThreshold(res.Name)
+	d.Notice("%s阈值已恢复为配置文件中的 %d%s", res.Label, value, res.Unit)
+}
+
+func (d *Dashboard) redraw() {
+	width, height := terminalSize()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	select {
+	case <-d.done:
+		return
+	default:
+	}
+
+	lines := d.render(width)
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	fmt.Fprint(d.out, ansiHome+strings.Join(lines, ansiClearLine+"\r\n")+ansiClearLine+ansiClearDown)
+}
+
+// render 生成整屏内容，调用方持有 d.mu
+func (d *Dashboard) render(width int) []string {
+	var lines []string
+
+	header := fmt.Sprintf("%s%s MikaBooM v%s%s", ansiBold, ansiCyan, version.GetVersion(), ansiReset)
+	if !d.snap.Time.IsZero() {
+		header += "  " + d.snap.Time.Format("15:04:05")
+	}
+	for _, note := range d.snap.Notes {
+		header += "  " + ansiMagenta + note + ansiReset
+	}
+	lines = append(lines, header, "")
+
+	barWidth := clampInt(width-56, 10, 40)
+	sparkWidth := clampInt(width-8, 10, historySize)
+
+	for i, res := range d.ctrl.Registry().All() {
+		state, _ := d.snap.Get(res.Name)
+		history := d.history[res.Name]
+		scale := barScale(res, state, history)
+
+		marker := "  "
+		if i == d.selected {
+			marker = ansiBold + "> " + ansiReset
+		}
+
+		threshold := fmt.Sprintf("阈值 %d%s", state.Threshold, res.Unit)
+		if base, override := d.ctrl.BaseThreshold(res.Name); override {
+			threshold += fmt.Sprintf(" (手动 %d)", base)
+		}
+
+		lines = append(lines, fmt.Sprintf("%s%s%-4s%s %s %s%6.1f%s%s  其他 %.1f  %s",
+			marker, ansiBold, res.Tag, ansiReset,
+			bar(state.Usage, float64(state.Threshold), scale, barWidth),
+			usageColor(state.Usage, state.Threshold), state.Usage, res.Unit, ansiReset,
+			state.OtherUsage, threshold))
+		lines = append(lines, "      "+workerLine(res, state))
+		lines = append(lines, "      "+ansiDim+sparkline(history, scale, sparkWidth)+ansiReset)
+		lines = append(lines, "")
+	}
+
+	if len(d.cores) > 0 {
+		lines = append(lines, ansiBold+"每核心占用"+ansiReset)
+		const cellWidth = 20
+		cols := clampInt(width/cellWidth, 1, len(d.cores))
+		for start := 0; start < len(d.cores); start += cols {
+			var row strings.Builder
+			for i := start; i < start+cols && i < len(d.cores); i++ {
+				usage := d.cores[i]
+				fmt.Fprintf(&row, " %3d %s%s%s %3.0f%%", i, usageColor(usage, 100), blocks(usage, 100, 8), ansiReset, usage)
+			}
+			lines = append(lines, row.String())
+		}
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, ansiDim+" ↑/↓ 选择  p 暂停/恢复  +/- 阈值±5  r 恢复配置阈值  q 退出"+ansiReset)
+	if d.message != "" {
+		lines = append(lines, " "+d.message)
+	}
+	return lines
+}
+
+func workerLine(res *resource.Resource, state controller.ResourceState) string {
+	if res.Loader == nil {
+		return ansiDim + "工作器: 无" + ansiReset
+	}
+	if state.Paused {
+		return ansiYellow + "工作器: 已暂停" + ansiReset
+	}
+	if !res.Loader.IsRunning() {
+		return ansiDim + "工作器: 已停止" + ansiReset
+	}
+
+	line := fmt.Sprintf("%s工作器: 运行中%s  占用 %.1f%s  目标 %.1f%s", ansiGreen, ansiReset, state.WorkerUsage, res.Unit, state.Target, res.Unit)
+	if l, ok := res.Loader.(interface{ GetIntensity() int32 }); ok {
+		line += fmt.Sprintf("  强度 %d%%", l.GetIntensity())
+	}
+	if l, ok := res.Loader.(interface{ GetCoreIntensities() []int32 }); ok {
+		if cores := l.GetCoreIntensities(); len(cores) > 0 {
+			lo, hi := cores[0], cores[0]
+			for _, v := range cores {
+				if v < lo {
+					lo = v
+				}
+				if v > hi {
+					hi = v
+				}
+			}
+			line += fmt.Sprintf(" (各核心 %d-%d%%)", lo, hi)
+		}
+	}
+	if l, ok := res.Loader.(interface{ GetAllocatedSize() int64 }); ok {
+		line += fmt.Sprintf("  已分配 %dMB", l.GetAllocatedSize()/1024/1024)
+	} else if reporter, ok := res.Loader.(worker.Reporter); ok {
+		line += "  " + reporter.Status()
+	}
+	return line
+}
+
+// barScale 百分比资源按 100 绘制，其余资源按阈值和历史最大值自动缩放
+func barScale(res *resource.Resource, state controller.ResourceState, history []float64) float64 {
+	if res.Unit == "%" {
+		return 100
+	}
+
+	scale := float64(state.Threshold) * 1.5
+	for _, v := range history {
+		scale = math.Max(scale, v)
+	}
+	return math.Max(scale, 1)
+}
+
+// bar 绘制占用条，阈值位置以 │ 标出
+func bar(value, threshold, scale float64, width int) string {
+	filled := clampInt(int(value/scale*float64(width)+0.5), 0, width)
+	mark := -1
+	if threshold > 0 && threshold <= scale {
+		mark = clampInt(int(threshold/scale*float64(width)), 0, width-1)
+	}
+
+	var b strings.Builder
+	b.WriteString("[")
+	for i := 0; i < width; i++ {
+		switch {
+		case i == mark:
+			b.WriteString(ansiYellow + "│" + ansiReset)
+		case i < filled:
+			b.WriteString("█")
+		default:
+			// 阈值标记之后的空白部分一次性输出
+			end := width
+			if mark > i {
+				end = mark
+			}
+			b.WriteString(ansiDim + strings.Repeat("░", end-i) + ansiReset)
+			i = end - 1
+		}
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+func blocks(value, scale float64, width int) string {
+	filled := clampInt(int(value/scale*float64(width)+0.5), 0, width)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// sparkline 绘制最近 width 个样本
+func sparkline(values []float64, scale float64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	runes := make([]rune, len(values))
+	for i, v := range values {
+		level := clampInt(int(v/scale*float64(len(sparkBlocks)-1)+0.5), 0, len(sparkBlocks)-1)
+		runes[i] = sparkBlocks[level]
+	}
+	return string(runes)
+}
+
+// usageColor 与控制台输出一致：超过阈值为红色，超过阈值 80% 为黄色
+func usageColor(usage float64, threshold int) string {
+	switch {
+	case usage > float64(threshold):
+		return ansiRed
+	case usage > float64(threshold)*0.8:
+		return ansiYellow
+	}
+	return ansiGreen
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
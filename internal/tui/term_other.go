@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !windows
+
+package tui
+
+import "errors"
+
+// makeRaw 当前平台不支持切换终端模式
+func makeRaw() (func(), error) {
+	return nil, errors.New("当前平台不支持终端仪表盘")
+}
+
+func terminalSize() (int, int) {
+	return defaultWidth, defaultHeight
+}
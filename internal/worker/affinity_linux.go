@@ -0,0 +1,30 @@
+//go:build linux
+
+package worker
+
+import "golang.org/x/sys/unix"
+
+// allowedCPUs 返回当前进程允许运行的 CPU 编号
+func allowedCPUs() []int {
+	var set unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &set); err != nil {
+		return nil
+	}
+
+	count := set.Count()
+	var cpus []int
+	for i := 0; len(cpus) < count; i++ {
+		if set.IsSet(i) {
+			cpus = append(cpus, i)
+		}
+	}
+	return cpus
+}
+
+// pinThread 将调用线程绑定到指定 CPU
+// 调用方必须已通过 runtime.LockOSThread 绑定线程
+func pinThread(cpu int) error {
+	var set unix.CPUSet
+	set.Set(cpu)
+	return unix.SchedSetaffinity(0, &set)
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package worker
+
+import "errors"
+
+// allowedCPUs 当前平台不支持绑定核心，由调用方回退到整机平均调节
+func allowedCPUs() []int {
+	return nil
+}
+
+func pinThread(cpu int) error {
+	return errors.New("当前平台不支持绑定 CPU 核心")
+}
@@ -0,0 +1,51 @@
+package worker
+
+import "runtime/debug"
+
+// AllocatorMode 内存工作器分配内存块的方式
+type AllocatorMode int
+
+const (
+	AllocHeap AllocatorMode = iota // Go 堆，释放后由 GC 和 scavenger 决定何时归还系统
+	AllocMmap                      // 匿名映射，释放时立即归还系统
+)
+
+// allocator 内存块的分配和释放
+type allocator interface {
+	alloc(size int) ([]byte, error)
+	free(b []byte)
+	// flush 将已释放但仍驻留的内存归还系统
+	flush()
+}
+
+func newAllocator(mode AllocatorMode) allocator {
+	if mode == AllocMmap {
+		return mmapAllocator{}
+	}
+	return heapAllocator{}
+}
+
+type heapAllocator struct{}
+
+func (heapAllocator) alloc(size int) ([]byte, error) {
+	return make([]byte, size), nil
+}
+
+func (heapAllocator) free(b []byte) {}
+
+func (heapAllocator) flush() {
+	debug.FreeOSMemory()
+}
+
+// mmapAllocator 内存不经过 Go 堆，free 后立即归还系统，flush 无需操作
+type mmapAllocator struct{}
+
+func (mmapAllocator) alloc(size int) ([]byte, error) {
+	return mapAnon(size)
+}
+
+func (mmapAllocator) free(b []byte) {
+	unmapAnon(b)
+}
+
+func (mmapAllocator) flush() {}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !windows
+
+package worker
+
+// mapAnon 当前平台不支持匿名映射，使用 Go 堆
+func mapAnon(size int) ([]byte, error) {
+	return make([]byte, size), nil
+}
+
+func unmapAnon(b []byte) {}
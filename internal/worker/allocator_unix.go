@@ -0,0 +1,22 @@
+//go:build linux || darwin
+
+package worker
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// mapAnon 分配匿名私有映射
+func mapAnon(size int) ([]byte, error) {
+	b, err := unix.Mmap(-1, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_PRIVATE|unix.MAP_ANON)
+	if err != nil {
+		return nil, fmt.Errorf("mmap 失败: %w", err)
+	}
+	return b, nil
+}
+
+func unmapAnon(b []byte) {
+	unix.Munmap(b)
+}
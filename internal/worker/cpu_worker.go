@@ -1,13 +1,22 @@
 package worker
 
 import (
+	"fmt"
+	"log"
 	"math"
 	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"MikaBooM/internal/pid"
+	"MikaBooM/internal/psi"
 )
 
+// maxControlStep 单次控制计算使用的最大时间步长
+// 调节间隔过长时按上限计算，避免积分步长过大导致振荡
+const maxControlStep = 2 * time.Second
+
 type CPUWorker struct {
 	threshold  int
 	running    atomic.Bool
@@ -20,20 +29,57 @@ type CPUWorker struct {
 	// 性能统计
 	lastAdjustTime time.Time
 	adjustMutex    sync.Mutex
+	controller     *pid.Controller
+
+	// 实测 CPU 时间
+	cpuTime        atomic.Int64 // 所有工作线程累计消耗的 CPU 时间（纳秒）
+	measured       atomic.Bool  // 当前平台是否支持按线程统计
+	sampleMutex    sync.Mutex
+	lastSampleTime time.Time
+	lastSampleCPU  int64
+
+	// 按核心调节
+	coreSource    func() []float64 // 各核心最近一次的系统总占用，按 CPU 编号索引，为 nil 时不按核心调节
+	cores         []int            // 各工作线程绑定的 CPU 编号，为空时不绑定
+	coreIntensity []atomic.Int32   // 各工作线程的强度
+	threadCPU     []atomic.Int64   // 各工作线程累计消耗的 CPU 时间（纳秒）
+	lastCoreCPU   []int64
+	lastCoreTime  time.Time
+
+	priority Priority      // 工作线程的调度优先级
+	capacity float64       // 占用率 100% 对应的核心数
+	workload Workload      // 工作线程执行的计算类型
+	kernel   kernelOptions // 创建 Kernel 时使用的设置
+
+	pressure  PressureLimit // CPU 压力上限，超过时立即停止加负载
+	holdUntil time.Time     // 紧急退让后暂停调节的截止时间，由 adjustMutex 保护
 }
 
+// minSampleInterval 两次实测采样的最小间隔，间隔过短时返回上一次结果
+const minSampleInterval = 500 * time.Millisecond
+
 func NewCPUWorker(threshold int) *CPUWorker {
 	w := &CPUWorker{
 		threshold:      threshold,
 		workers:        runtime.NumCPU(),
 		stopChan:       make(chan struct{}),
 		lastAdjustTime: time.Now(),
+		controller:     pid.New(0.2, 0.2, 0, 0, 100),
+		capacity:       float64(runtime.NumCPU()),
 	}
 	w.usage.Store(0.0)
 	w.intensity.Store(30) // 初始强度30%
+	w.allocThreads()
 	return w
 }
 
+// allocThreads 按工作线程数分配各线程的状态
+func (w *CPUWorker) allocThreads() {
+	w.coreIntensity = make([]atomic.Int32, w.workers)
+	w.threadCPU = make([]atomic.Int64, w.workers)
+	w.lastCoreCPU = make([]int64, w.workers)
+}
+
 func (w *CPUWorker) Start() {
 	if w.running.Load() {
 		return
@@ -42,11 +88,67 @@ func (w *CPUWorker) Start() {
 	w.running.Store(true)
 	w.stopChan = make(chan struct{})
 
+	// 以当前强度作为控制器初值，避免启动时强度跳变
+	w.adjustMutex.Lock()
+	w.controller.Reset(float64(w.intensity.Load()))
+	w.lastAdjustTime = time.Now()
+	w.adjustMutex.Unlock()
+
+	w.sampleMutex.Lock()
+	w.lastSampleTime = time.Now()
+	w.lastSampleCPU = w.cpuTime.Load()
+	w.sampleMutex.Unlock()
+
+	for i := range w.threadCPU {
+		w.lastCoreCPU[i] = w.threadCPU[i].Load()
+	}
+	w.lastCoreTime = time.Now()
+	w.setCoreIntensity(w.intensity.Load())
+
 	// 为每个CPU核心启动一个工作协程
 	for i := 0; i < w.workers; i++ {
 		w.wg.Add(1)
 		go w.work(i)
 	}
+
+	if w.pressure.enabled() {
+		w.wg.Add(1)
+		go w.watchPressure()
+	}
+}
+
+// watchPressure CPU 压力超过上限时立即将强度降为 0，不等待下一次调节，并在一段时间内不再增加
+func (w *CPUWorker) watchPressure() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(pressureCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stopChan:
+			return
+		case <-ticker.C:
+			p, err := psi.Read("cpu")
+			if err != nil {
+				log.Printf("无法读取 CPU 压力，停止检查: %v", err)
+				return
+			}
+			if !w.pressure.exceeded(p) {
+				continue
+			}
+
+			w.adjustMutex.Lock()
+			if w.intensity.Load() > 0 {
+				log.Printf("CPU 压力过高 (some %.1f%%)，立即停止加负载", p.Some.Avg10)
+			}
+			w.intensity.Store(0)
+			w.setCoreIntensity(0)
+			w.controller.Reset(0)
+			w.holdUntil = time.Now().Add(pressureHold)
+			w.adjustMutex.Unlock()
+		}
+	}
 }
 
 func (w *CPUWorker) Stop() {
@@ -65,7 +167,28 @@ func (w *CPUWorker) work(id int) {
 
 	// 绑定到特定的OS线程
 	runtime.LockOSThread()
-	defer runtime.UnlockOSThread()
+	if w.priority.lowered() {
+		// 降低了优先级的线程不解除绑定，goroutine 退出时线程随之销毁
+		if err := lowerThreadPriority(w.priority); err != nil && id == 0 {
+			log.Printf("CPU 工作线程无法降低优先级: %v", err)
+		}
+	} else {
+		defer runtime.UnlockOSThread()
+	}
+
+	pinned := false
+	if id < len(w.cores) {
+		if err := pinThread(w.cores[id]); err != nil {
+			log.Printf("CPU 工作线程绑定核心 %d 失败，按整机平均占用调节: %v", w.cores[id], err)
+		} else {
+			pinned = true
+		}
+	}
+
+	kernel := newKernel(w.workload, w.kernel)
+
+	lastCPU, ok := threadCPUTime()
+	w.measured.Store(ok)
 
 	for {
 		select {
@@ -73,7 +196,10 @@ func (w *CPUWorker) work(id int) {
 			return
 		default:
 			intensity := w.intensity.Load()
-			
+			if pinned {
+				intensity = w.coreIntensity[id].Load()
+			}
+
 			// 根据强度调整工作时间和休息时间
 			// 使用微秒级精度
 			workDuration := time.Duration(intensity) * time.Microsecond * 100
@@ -82,43 +208,22 @@ func (w *CPUWorker) work(id int) {
 			// 执行计算密集型任务
 			start := time.Now()
 			for time.Since(start) < workDuration {
-				// 1. 数学计算：计算圆周率（使用 Leibniz 公式）
-				piResult := 0.0
-				for i := 0; i < 1000; i++ {
-					piResult += math.Pow(-1, float64(i)) / (2*float64(i) + 1)
-				}
-				_ = piResult * 4
-				
-				// 2. 三角函数计算
-				for i := 0; i < 100; i++ {
-					angle := float64(i) * 0.1
-					_ = math.Sin(angle) * math.Cos(angle) * math.Tan(angle)
-				}
-				
-				// 3. 矩阵运算
-				matrix := make([][]float64, 10)
-				for i := range matrix {
-					matrix[i] = make([]float64, 10)
-					for j := range matrix[i] {
-						matrix[i][j] = float64(i*j) * math.Sin(float64(i+j))
-					}
-				}
-				
-				// 4. 对数和指数运算
-				for i := 1; i < 100; i++ {
-					_ = math.Log(float64(i)) * math.Exp(float64(i%10))
-				}
-				
-				// 5. 平方根和幂运算
-				for i := 1; i < 100; i++ {
-					_ = math.Sqrt(float64(i)) * math.Pow(float64(i), 1.5)
-				}
+				kernel.Run()
 			}
 
 			// 休息
 			if sleepDuration > 0 {
 				time.Sleep(sleepDuration)
 			}
+
+			// 累计本线程的实际 CPU 时间
+			if ok {
+				if now, valid := threadCPUTime(); valid {
+					w.cpuTime.Add(int64(now - lastCPU))
+					w.threadCPU[id].Add(int64(now - lastCPU))
+					lastCPU = now
+				}
+			}
 		}
 	}
 }
@@ -131,65 +236,193 @@ func (w *CPUWorker) AdjustLoad(currentWorkerUsage, targetWorkerUsage float64) {
 	w.adjustMutex.Lock()
 	defer w.adjustMutex.Unlock()
 
-	if time.Since(w.lastAdjustTime) < 500*time.Millisecond {
+	elapsed := time.Since(w.lastAdjustTime)
+	if elapsed < 500*time.Millisecond {
 		return
 	}
 	w.lastAdjustTime = time.Now()
+	if elapsed > maxControlStep {
+		elapsed = maxControlStep
+	}
+
+	// 紧急退让后的一段时间内保持强度为 0
+	if w.lastAdjustTime.Before(w.holdUntil) {
+		return
+	}
+
+	// PID 控制器直接输出工作强度
+	output := w.controller.Update(targetWorkerUsage, currentWorkerUsage, elapsed)
+	intensity := int32(math.Round(output))
+	w.intensity.Store(intensity)
+
+	if len(w.cores) > 0 {
+		w.distribute(float64(intensity))
+	}
+}
 
-	diff := targetWorkerUsage - currentWorkerUsage
-	currentIntensity := w.intensity.Load()
-	newIntensity := currentIntensity
-
-	// 根据差值调整强度（更精细的控制）
-	if diff > 20 {
-		newIntensity += 10
-	} else if diff > 10 {
-		newIntensity += 5
-	} else if diff > 5 {
-		newIntensity += 3
-	} else if diff > 2 {
-		newIntensity += 2
-	} else if diff > 0.5 {
-		newIntensity += 1
-	} else if diff < -20 {
-		newIntensity -= 10
-	} else if diff < -10 {
-		newIntensity -= 5
-	} else if diff < -5 {
-		newIntensity -= 3
-	} else if diff < -2 {
-		newIntensity -= 2
-	} else if diff < -0.5 {
-		newIntensity -= 1
-	}
-
-	if newIntensity < 0 {
-		newIntensity = 0
-	} else if newIntensity > 100 {
-		newIntensity = 100
-	}
-
-	w.intensity.Store(newIntensity)
+// distribute 按各核心的空闲程度分配强度，各核心强度的平均值与整机强度一致
+// 其他程序占满的核心强度为 0，较空闲的核心相应提高
+func (w *CPUWorker) distribute(intensity float64) {
+	usages := w.coreSource()
+
+	now := time.Now()
+	elapsed := now.Sub(w.lastCoreTime)
+	w.lastCoreTime = now
+
+	free := make([]float64, len(w.cores))
+	var sum float64
+	for i, cpu := range w.cores {
+		total := w.threadCPU[i].Load()
+		own := 0.0
+		if elapsed > 0 {
+			own = float64(total-w.lastCoreCPU[i]) / float64(elapsed) * 100
+		}
+		w.lastCoreCPU[i] = total
+
+		// 尚未采样或编号超出范围时视为空闲
+		other := 0.0
+		if cpu < len(usages) {
+			other = usages[cpu] - own
+		}
+		free[i] = math.Min(math.Max(100-other, 0), 100)
+		sum += free[i]
+	}
+
+	mean := sum / float64(len(free))
+	for i := range free {
+		v := 0.0
+		if mean > 0 {
+			v = intensity * free[i] / mean
+		}
+		w.coreIntensity[i].Store(int32(math.Round(math.Min(v, 100))))
+	}
+}
+
+// setCoreIntensity 将所有工作线程设置为相同强度
+func (w *CPUWorker) setCoreIntensity(intensity int32) {
+	for i := range w.coreIntensity {
+		w.coreIntensity[i].Store(intensity)
+	}
+}
+
+// SetPerCore 设置各核心占用的来源，工作线程将绑定到进程允许的各个核心并按该核心的占用调节强度
+// 当前平台不支持绑定核心时仍按整机平均占用调节，需在 Start 之前调用
+func (w *CPUWorker) SetPerCore(source func() []float64) {
+	w.coreSource = source
+	w.cores = allowedCPUs()
+	if len(w.cores) == 0 {
+		return
+	}
+
+	w.workers = len(w.cores)
+	w.allocThreads()
+}
+
+// SetPressureLimit 设置 CPU 压力上限，超过时立即停止加负载，下次启动时生效
+func (w *CPUWorker) SetPressureLimit(l PressureLimit) {
+	w.pressure = l
+}
+
+// SetCapacity 设置占用率 100% 对应的核心数，例如 cgroup 的 CPU 上限
+func (w *CPUWorker) SetCapacity(cores float64) {
+	if cores > 0 {
+		w.capacity = cores
+	}
+}
+
+// SetPriority 设置工作线程的调度优先级，下次启动时生效
+func (w *CPUWorker) SetPriority(p Priority) {
+	w.priority = p
+}
+
+// SetWorkload 设置工作线程执行的计算类型，下次启动时生效
+func (w *CPUWorker) SetWorkload(workload Workload) {
+	w.workload = workload
+}
+
+// SetMemoryKernelSize 设置 memory 计算类型每个工作线程使用的内存（字节），<= 0 为默认的 24MB，下次启动时生效
+func (w *CPUWorker) SetMemoryKernelSize(bytes int) {
+	w.kernel.memorySize = bytes
+}
+
+// IsPerCore 返回工作线程是否已绑定到各个核心
+func (w *CPUWorker) IsPerCore() bool {
+	return len(w.cores) > 0
 }
 
+// GetCoreIntensities 获取各工作线程的强度，未按核心调节时返回 nil
+func (w *CPUWorker) GetCoreIntensities() []int32 {
+	if len(w.cores) == 0 {
+		return nil
+	}
+
+	intensities := make([]int32, len(w.coreIntensity))
+	for i := range w.coreIntensity {
+		intensities[i] = w.coreIntensity[i].Load()
+	}
+	return intensities
+}
+
+// SetPIDGains 设置强度调节控制器的增益
+func (w *CPUWorker) SetPIDGains(kp, ki, kd float64) {
+	w.controller.SetGains(kp, ki, kd)
+}
+
+// GetUsage 获取工作器占整机 CPU 的百分比
+// 支持的平台上根据工作线程实测 CPU 时间计算，否则根据工作强度估算
 func (w *CPUWorker) GetUsage() float64 {
 	if !w.running.Load() {
 		return 0
 	}
-	
-	// 根据工作强度估算CPU占用
-	// 这是一个粗略估算，实际占用会受系统调度影响
+
+	if !w.measured.Load() {
+		return w.estimateUsage()
+	}
+
+	w.sampleMutex.Lock()
+	defer w.sampleMutex.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(w.lastSampleTime)
+	if elapsed < minSampleInterval {
+		return w.usage.Load().(float64)
+	}
+
+	total := w.cpuTime.Load()
+	consumed := time.Duration(total - w.lastSampleCPU)
+	w.lastSampleTime = now
+	w.lastSampleCPU = total
+
+	usage := consumed.Seconds() / (elapsed.Seconds() * w.capacity) * 100.0
+	if usage < 0 {
+		usage = 0
+	} else if usage > 100 {
+		usage = 100
+	}
+
+	w.usage.Store(usage)
+	return usage
+}
+
+// estimateUsage 根据工作强度估算CPU占用
+// 这是一个粗略估算，实际占用会受系统调度影响
+func (w *CPUWorker) estimateUsage() float64 {
 	intensity := float64(w.intensity.Load())
-	estimatedUsage := (intensity / 100.0) * float64(w.workers) * 100.0 / float64(runtime.NumCPU())
-	
+	estimatedUsage := (intensity / 100.0) * float64(w.workers) * 100.0 / w.capacity
+
 	// 限制在合理范围内
 	if estimatedUsage > 100 {
 		estimatedUsage = 100
 	}
-	
+
 	return estimatedUsage
 }
 
+// IsMeasured 返回工作器占用是否为实测值
+func (w *CPUWorker) IsMeasured() bool {
+	return w.measured.Load()
+}
+
 func (w *CPUWorker) IsRunning() bool {
 	return w.running.Load()
 }
@@ -199,6 +432,16 @@ func (w *CPUWorker) GetIntensity() int32 {
 	return w.intensity.Load()
 }
 
+// ShortStatus 控制台显示的状态摘要
+func (w *CPUWorker) ShortStatus() string {
+	return fmt.Sprintf("I:%d%%", w.GetIntensity())
+}
+
+// Status 托盘显示的状态摘要
+func (w *CPUWorker) Status() string {
+	return fmt.Sprintf("强度:%d%%", w.GetIntensity())
+}
+
 // GetWorkerCount 获取工作线程数量
 func (w *CPUWorker) GetWorkerCount() int {
 	return w.workers
@@ -212,9 +455,13 @@ func (w *CPUWorker) SetIntensity(intensity int32) {
 		intensity = 100
 	}
 	w.intensity.Store(intensity)
+	w.setCoreIntensity(intensity)
+	w.controller.Reset(float64(intensity))
 }
 
 // ResetIntensity 重置工作强度到默认值
 func (w *CPUWorker) ResetIntensity() {
 	w.intensity.Store(30)
+	w.setCoreIntensity(30)
+	w.controller.Reset(30)
 }
\ No newline at end of file
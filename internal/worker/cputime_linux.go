@@ -0,0 +1,19 @@
+//go:build linux
+
+package worker
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// threadCPUTime 返回调用线程已消耗的 CPU 时间（用户态 + 内核态）
+// 调用方必须已通过 runtime.LockOSThread 绑定线程
+func threadCPUTime() (time.Duration, bool) {
+	var ru unix.Rusage
+	if err := unix.Getrusage(unix.RUSAGE_THREAD, &ru); err != nil {
+		return 0, false
+	}
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano()), true
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package worker
+
+import "time"
+
+// threadCPUTime 当前平台不支持按线程统计 CPU 时间，由调用方回退到强度估算
+func threadCPUTime() (time.Duration, bool) {
+	return 0, false
+}
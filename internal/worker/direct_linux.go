@@ -0,0 +1,8 @@
+//go:build linux
+
+package worker
+
+import "syscall"
+
+// directIOFlag 绕过页缓存直接读写磁盘
+const directIOFlag = syscall.O_DIRECT
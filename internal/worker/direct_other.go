@@ -0,0 +1,6 @@
+//go:build !linux
+
+package worker
+
+// directIOFlag 当前平台不支持 O_DIRECT，通过定期 Sync 保证写入落盘
+const directIOFlag = 0
@@ -0,0 +1,321 @@
+package worker
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+const (
+	diskBlockSize = 1024 * 1024 // 每次读写 1MB
+	diskAlignment = 4096        // O_DIRECT 要求的缓冲区对齐
+
+	diskScratchPrefix = "mikaboom-disk-"
+	diskScratchSuffix = ".tmp"
+)
+
+type DiskWorker struct {
+	threshold int
+	dir       string
+	fileSize  int64
+	direct    bool
+	maxMBps   float64
+	running   atomic.Bool
+	usage     atomic.Value // float64
+	rate      atomic.Int64 // 目标吞吐量（字节/秒）
+	processed atomic.Int64 // 累计读写字节数
+	directIO  atomic.Bool  // 当前是否实际使用了 O_DIRECT
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+	path      string
+
+	sampleMutex    sync.Mutex
+	lastSampleTime time.Time
+	lastSampleIO   int64
+
+	priority Priority // 工作线程的调度优先级
+}
+
+// NewDiskWorker 创建磁盘工作器，在 dir 下读写不超过 fileSizeMB 的临时文件
+func NewDiskWorker(threshold int, dir string, fileSizeMB int, direct bool, maxMBps int) *DiskWorker {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	size := int64(fileSizeMB) * 1024 * 1024
+	size -= size % diskBlockSize
+	if size < diskBlockSize {
+		size = diskBlockSize
+	}
+
+	w := &DiskWorker{
+		threshold: threshold,
+		dir:       dir,
+		fileSize:  size,
+		direct:    direct,
+		maxMBps:   float64(maxMBps),
+		stopChan:  make(chan struct{}),
+		path:      filepath.Join(dir, fmt.Sprintf("%s%d%s", diskScratchPrefix, os.Getpid(), diskScratchSuffix)),
+	}
+	w.usage.Store(0.0)
+	return w
+}
+
+func (w *DiskWorker) Start() {
+	if w.running.Load() {
+		return
+	}
+
+	w.running.Store(true)
+	w.stopChan = make(chan struct{})
+
+	w.sampleMutex.Lock()
+	w.lastSampleTime = time.Now()
+	w.lastSampleIO = w.processed.Load()
+	w.sampleMutex.Unlock()
+
+	w.wg.Add(1)
+	go w.work()
+}
+
+// SetPriority 设置读写线程的调度优先级，下次启动时生效
+func (w *DiskWorker) SetPriority(p Priority) {
+	w.priority = p
+}
+
+// Stop 停止读写并删除临时文件
+func (w *DiskWorker) Stop() {
+	if !w.running.Load() {
+		return
+	}
+
+	w.running.Store(false)
+	close(w.stopChan)
+	w.wg.Wait()
+	w.usage.Store(0.0)
+	w.rate.Store(0)
+}
+
+func (w *DiskWorker) openScratch() (*os.File, bool, error) {
+	if err := os.MkdirAll(w.dir, 0755); err != nil {
+		return nil, false, fmt.Errorf("创建磁盘工作目录失败: %w", err)
+	}
+	w.removeStaleScratch()
+
+	flags := os.O_RDWR | os.O_CREATE | os.O_TRUNC
+	if w.direct && directIOFlag != 0 {
+		f, err := os.OpenFile(w.path, flags|directIOFlag, 0600)
+		if err == nil {
+			return f, true, nil
+		}
+		// 部分文件系统（如 tmpfs）不支持 O_DIRECT，回退到普通读写
+		log.Printf("磁盘工作器无法使用 O_DIRECT，回退到普通读写: %v", err)
+	}
+
+	f, err := os.OpenFile(w.path, flags, 0600)
+	if err != nil {
+		return nil, false, fmt.Errorf("创建磁盘临时文件失败: %w", err)
+	}
+	return f, false, nil
+}
+
+// removeStaleScratch 删除异常退出的实例留下的临时文件，仍在运行的实例的文件保留
+func (w *DiskWorker) removeStaleScratch() {
+	matches, err := filepath.Glob(filepath.Join(w.dir, diskScratchPrefix+"*"+diskScratchSuffix))
+	if err != nil {
+		return
+	}
+
+	for _, path := range matches {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), diskScratchPrefix), diskScratchSuffix)
+		pid, err := strconv.ParseInt(name, 10, 32)
+		if err != nil || int(pid) == os.Getpid() {
+			continue
+		}
+		if alive, err := process.PidExists(int32(pid)); err == nil && alive {
+			continue
+		}
+		if err := os.Remove(path); err == nil {
+			log.Printf("已删除残留的磁盘临时文件: %s", path)
+		}
+	}
+}
+
+func (w *DiskWorker) work() {
+	defer w.wg.Done()
+
+	if w.priority.lowered() {
+		// 读写在当前线程上同步执行，绑定线程后优先级才对磁盘读写生效
+		// 降低了优先级的线程不解除绑定，goroutine 退出时线程随之销毁
+		runtime.LockOSThread()
+		if err := lowerThreadPriority(w.priority); err != nil {
+			log.Printf("磁盘工作线程无法降低优先级: %v", err)
+		}
+	}
+
+	f, direct, err := w.openScratch()
+	if err != nil {
+		log.Printf("%v", err)
+		return
+	}
+	w.directIO.Store(direct)
+	defer func() {
+		f.Close()
+		os.Remove(w.path)
+	}()
+
+	buf := alignedBuffer(diskBlockSize, diskAlignment)
+	for i := range buf {
+		buf[i] = byte(i * 131)
+	}
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	blocks := w.fileSize / diskBlockSize
+	block := int64(0)
+	writing := true
+	budget := 0.0
+	last := time.Now()
+
+	for {
+		select {
+		case <-w.stopChan:
+			return
+		case now := <-ticker.C:
+			rate := float64(w.rate.Load())
+			budget += rate * now.Sub(last).Seconds()
+			last = now
+
+			// 最多累积 100ms 的额度，低速率时至少允许一个块
+			limit := rate / 10
+			if limit < diskBlockSize {
+				limit = diskBlockSize
+			}
+			if budget > limit {
+				budget = limit
+			}
+
+			for budget >= diskBlockSize {
+				offset := block * diskBlockSize
+				if writing {
+					_, err = f.WriteAt(buf, offset)
+				} else {
+					_, err = f.ReadAt(buf, offset)
+				}
+				if err != nil {
+					log.Printf("磁盘工作器读写失败: %v", err)
+					return
+				}
+
+				w.processed.Add(diskBlockSize)
+				budget -= diskBlockSize
+
+				block++
+				if block == blocks {
+					block = 0
+					if direct {
+						// 直接 I/O 时读写交替；普通读写时读取会命中页缓存，只做写入
+						writing = !writing
+					} else {
+						f.Sync()
+					}
+				}
+			}
+		}
+	}
+}
+
+func (w *DiskWorker) AdjustLoad(currentWorkerUsage, targetWorkerUsage float64) {
+	if !w.running.Load() {
+		return
+	}
+
+	// 读写速率由令牌桶精确限制，直接按目标吞吐量设置
+	target := targetWorkerUsage
+	if target < 0 {
+		target = 0
+	}
+	if w.maxMBps > 0 && target > w.maxMBps {
+		target = w.maxMBps
+	}
+
+	w.rate.Store(int64(target * 1024 * 1024))
+}
+
+// GetUsage 获取工作器实际读写吞吐量（MB/s）
+func (w *DiskWorker) GetUsage() float64 {
+	if !w.running.Load() {
+		return 0
+	}
+
+	w.sampleMutex.Lock()
+	defer w.sampleMutex.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(w.lastSampleTime)
+	if elapsed < minSampleInterval {
+		return w.usage.Load().(float64)
+	}
+
+	total := w.processed.Load()
+	usage := float64(total-w.lastSampleIO) / elapsed.Seconds() / 1024 / 1024
+	w.lastSampleTime = now
+	w.lastSampleIO = total
+
+	w.usage.Store(usage)
+	return usage
+}
+
+func (w *DiskWorker) IsRunning() bool {
+	return w.running.Load()
+}
+
+// GetRate 获取目标吞吐量（MB/s）
+func (w *DiskWorker) GetRate() float64 {
+	return float64(w.rate.Load()) / 1024 / 1024
+}
+
+// GetProcessedBytes 获取累计读写字节数
+func (w *DiskWorker) GetProcessedBytes() int64 {
+	return w.processed.Load()
+}
+
+// GetPath 获取临时文件路径
+func (w *DiskWorker) GetPath() string {
+	return w.path
+}
+
+// ShortStatus 控制台显示的状态摘要
+func (w *DiskWorker) ShortStatus() string {
+	return fmt.Sprintf("R:%.1fMB/s", w.GetRate())
+}
+
+// Status 托盘显示的状态摘要
+func (w *DiskWorker) Status() string {
+	mode := "缓存"
+	if w.directIO.Load() {
+		mode = "直接I/O"
+	}
+	return fmt.Sprintf("速率:%.1fMB/s, %s", w.GetRate(), mode)
+}
+
+// alignedBuffer 分配起始地址按 align 对齐的缓冲区
+func alignedBuffer(size, align int) []byte {
+	raw := make([]byte, size+align)
+	offset := 0
+	if rem := int(uintptr(unsafe.Pointer(&raw[0])) & uintptr(align-1)); rem != 0 {
+		offset = align - rem
+	}
+	return raw[offset : offset+size]
+}
@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRemoveStaleScratch(t *testing.T) {
+	dir := t.TempDir()
+	w := NewDiskWorker(10, dir, 1, false, 10)
+
+	scratch := func(name string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("x"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// 4194304 超出常见系统的 pid 上限，视为已退出的实例
+	stale := scratch(fmt.Sprintf("%s%d%s", diskScratchPrefix, 4194304, diskScratchSuffix))
+	own := scratch(filepath.Base(w.path))
+	live := scratch(fmt.Sprintf("%s%d%s", diskScratchPrefix, os.Getppid(), diskScratchSuffix))
+	other := scratch(diskScratchPrefix + "notes" + diskScratchSuffix)
+
+	w.removeStaleScratch()
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatalf("stale scratch file was kept: %v", err)
+	}
+	for _, path := range []string{own, live, other} {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("%s was removed: %v", filepath.Base(path), err)
+		}
+	}
+}
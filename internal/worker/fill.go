@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"encoding/binary"
+	"sync/atomic"
+)
+
+// FillMode 内存块写入内容的方式
+type FillMode int
+
+const (
+	FillSparse FillMode = iota // 每个页面写入一个字节，内容在各内存块之间相同
+	FillRandom                 // 写满伪随机数据，每个内存块各不相同
+)
+
+const pageSize = 4096
+
+// fillSeed 为每个内存块生成不同的随机种子
+var fillSeed atomic.Uint64
+
+// splitmix64 伪随机数生成器，速度足以填充数 GB 内存
+type splitmix64 uint64
+
+func (s *splitmix64) next() uint64 {
+	*s += 0x9e3779b97f4a7c15
+	z := uint64(*s)
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// fillChunk 按 mode 写入内存块，确保页面真实分配
+func fillChunk(b []byte, mode FillMode) {
+	if mode != FillRandom {
+		for j := 0; j < len(b); j += pageSize {
+			b[j] = byte(j % 256)
+		}
+		return
+	}
+
+	// 内容随机且各块不同，KSM 无法合并，zram 也无法压缩
+	rng := splitmix64(fillSeed.Add(1))
+	j := 0
+	for ; j+8 <= len(b); j += 8 {
+		binary.LittleEndian.PutUint64(b[j:], rng.next())
+	}
+	for ; j < len(b); j++ {
+		b[j] = byte(rng.next())
+	}
+}
+
+// touchChunk 改写每个页面开头的 8 字节，使页面保持活跃并且内容持续变化
+func touchChunk(b []byte, rng *splitmix64) {
+	for j := 0; j+8 <= len(b); j += pageSize {
+		binary.LittleEndian.PutUint64(b[j:], rng.next())
+	}
+}
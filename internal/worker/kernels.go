@@ -0,0 +1,230 @@
+package worker
+
+import (
+	"compress/flate"
+	"crypto/sha256"
+	"io"
+	"math"
+)
+
+func init() {
+	registerKernel(WorkloadMix, func(kernelOptions) Kernel { return &mixKernel{} })
+	registerKernel(WorkloadInteger, func(kernelOptions) Kernel { return &integerKernel{state: 0x9e3779b97f4a7c15} })
+	registerKernel(WorkloadFloat, func(kernelOptions) Kernel { return &floatKernel{} })
+	registerKernel(WorkloadSHA256, newSHA256Kernel)
+	registerKernel(WorkloadCompress, newCompressKernel)
+	registerKernel(WorkloadMemory, newMemoryKernel)
+	registerKernel(WorkloadVector, newVectorKernel)
+}
+
+// mixKernel 圆周率、三角函数、矩阵、对数和幂运算的混合负载
+type mixKernel struct {
+	matrix [10][10]float64
+	sink   float64
+}
+
+func (k *mixKernel) Run() {
+	// 1. 数学计算：计算圆周率（使用 Leibniz 公式）
+	piResult := 0.0
+	for i := 0; i < 1000; i++ {
+		piResult += math.Pow(-1, float64(i)) / (2*float64(i) + 1)
+	}
+	k.sink = piResult * 4
+
+	// 2. 三角函数计算
+	for i := 0; i < 100; i++ {
+		angle := float64(i) * 0.1
+		k.sink += math.Sin(angle) * math.Cos(angle) * math.Tan(angle)
+	}
+
+	// 3. 矩阵运算
+	for i := range k.matrix {
+		for j := range k.matrix[i] {
+			k.matrix[i][j] = float64(i*j) * math.Sin(float64(i+j))
+		}
+	}
+
+	// 4. 对数和指数运算
+	for i := 1; i < 100; i++ {
+		k.sink += math.Log(float64(i)) * math.Exp(float64(i%10))
+	}
+
+	// 5. 平方根和幂运算
+	for i := 1; i < 100; i++ {
+		k.sink += math.Sqrt(float64(i)) * math.Pow(float64(i), 1.5)
+	}
+}
+
+// integerKernel 乘法、移位、异或和旋转，只使用整数运算单元
+type integerKernel struct {
+	state uint64
+}
+
+func (k *integerKernel) Run() {
+	x := k.state
+	for i := 0; i < 8192; i++ {
+		x ^= x << 13
+		x ^= x >> 7
+		x ^= x << 17
+		x = x*0x5851f42d4c957f2d + uint64(i)
+		x = (x << 31) | (x >> 33)
+	}
+	k.state = x
+}
+
+// floatKernel 双精度乘加、除法和开方
+type floatKernel struct {
+	sink float64
+}
+
+func (k *floatKernel) Run() {
+	a, b, c := 1.0001, 0.9999, 0.5
+	for i := 0; i < 4096; i++ {
+		a = a*b + c
+		b = b*0.999999 + 1e-6
+		c = math.Sqrt(a*a+c) / (b + 1)
+	}
+	k.sink = a + b + c
+}
+
+// sha256Kernel 对 16KB 数据计算 SHA-256，每次以上一次的摘要开头
+type sha256Kernel struct {
+	buf []byte
+}
+
+func newSHA256Kernel(kernelOptions) Kernel {
+	k := &sha256Kernel{buf: make([]byte, 16*1024)}
+	fillChunk(k.buf, FillRandom)
+	return k
+}
+
+func (k *sha256Kernel) Run() {
+	sum := sha256.Sum256(k.buf)
+	copy(k.buf, sum[:])
+}
+
+// compressKernel 使用 compress/flate 压缩 4KB 类文本数据
+type compressKernel struct {
+	w   *flate.Writer
+	buf []byte
+}
+
+func newCompressKernel(kernelOptions) Kernel {
+	w, _ := flate.NewWriter(io.Discard, flate.DefaultCompression)
+	k := &compressKernel{w: w, buf: make([]byte, 4*1024)}
+
+	// 由少量字符组成的随机数据，压缩率与日志等文本接近
+	const alphabet = "etaoinshrdlu ,.\n"
+	rng := splitmix64(fillSeed.Add(1))
+	for i := range k.buf {
+		k.buf[i] = alphabet[rng.next()%uint64(len(alphabet))]
+	}
+	return k
+}
+
+func (k *compressKernel) Run() {
+	k.w.Reset(io.Discard)
+	k.w.Write(k.buf)
+	k.w.Close()
+}
+
+// DefaultMemoryKernelSize memoryKernel 默认使用的内存，三个数组共 24MB，大于常见的末级缓存
+const DefaultMemoryKernelSize = 24 * 1024 * 1024
+
+// memoryStreamStep memoryKernel 每次 Run 处理的元素数
+const memoryStreamStep = 32 * 1024
+
+// memoryKernel 按 STREAM triad (a = b + s*c) 顺序读写三个大数组，负载主要在内存带宽
+// 数组属于本进程但不属于内存工作器，其占用按其他程序的内存占用计算
+type memoryKernel struct {
+	a, b, c []float64
+	offset  int
+}
+
+func newMemoryKernel(opts kernelOptions) Kernel {
+	size := opts.memorySize
+	if size <= 0 {
+		size = DefaultMemoryKernelSize
+	}
+	// 每个数组的元素数取 memoryStreamStep 的整数倍，至少一段
+	n := size / 3 / 8 / memoryStreamStep * memoryStreamStep
+	if n < memoryStreamStep {
+		n = memoryStreamStep
+	}
+
+	k := &memoryKernel{
+		a: make([]float64, n),
+		b: make([]float64, n),
+		c: make([]float64, n),
+	}
+	for i := range k.b {
+		k.b[i] = float64(i)
+		k.c[i] = float64(n - i)
+	}
+	return k
+}
+
+func (k *memoryKernel) Run() {
+	end := k.offset + memoryStreamStep
+	a, b, c := k.a[k.offset:end], k.b[k.offset:end], k.c[k.offset:end]
+	for i := range a {
+		a[i] = b[i] + 3*c[i]
+	}
+	k.offset = end % len(k.a)
+}
+
+// vectorLen vectorKernel 每个数组的元素数，三个数组共 48KB，可以放入一级或二级缓存
+// 必须是 32 的整数倍，AVX 实现每次处理 32 个元素
+const vectorLen = 4096
+
+// vectorKernel 单精度 saxpy (z = a*x + y) 并累加 z 与 x 的点积
+// amd64 且 CPU 支持 AVX 时使用 256 位向量指令，否则为按 8 路展开的标量实现
+type vectorKernel struct {
+	x, y, z []float32
+	sink    float32
+}
+
+func newVectorKernel(kernelOptions) Kernel {
+	k := &vectorKernel{
+		x: make([]float32, vectorLen),
+		y: make([]float32, vectorLen),
+		z: make([]float32, vectorLen),
+	}
+	for i := range k.x {
+		k.x[i] = float32(i%97) * 0.01
+		k.y[i] = float32(i%89) * 0.02
+	}
+	return k
+}
+
+func (k *vectorKernel) Run() {
+	// AVX 实现一遍不到 1 微秒，重复数遍使每次 Run 的时长与其他计算类型接近
+	for i := 0; i < 8; i++ {
+		k.sink = saxpyDot(1.0001, k.x, k.y, k.z)
+	}
+}
+
+// saxpyDotGeneric saxpyDot 的标量实现，各路累加互不依赖
+func saxpyDotGeneric(alpha float32, x, y, z []float32) float32 {
+	var s0, s1, s2, s3, s4, s5, s6, s7 float32
+	for i := 0; i+8 <= len(z); i += 8 {
+		z[i] = alpha*x[i] + y[i]
+		z[i+1] = alpha*x[i+1] + y[i+1]
+		z[i+2] = alpha*x[i+2] + y[i+2]
+		z[i+3] = alpha*x[i+3] + y[i+3]
+		z[i+4] = alpha*x[i+4] + y[i+4]
+		z[i+5] = alpha*x[i+5] + y[i+5]
+		z[i+6] = alpha*x[i+6] + y[i+6]
+		z[i+7] = alpha*x[i+7] + y[i+7]
+
+		s0 += z[i] * x[i]
+		s1 += z[i+1] * x[i+1]
+		s2 += z[i+2] * x[i+2]
+		s3 += z[i+3] * x[i+3]
+		s4 += z[i+4] * x[i+4]
+		s5 += z[i+5] * x[i+5]
+		s6 += z[i+6] * x[i+6]
+		s7 += z[i+7] * x[i+7]
+	}
+	return s0 + s1 + s2 + s3 + s4 + s5 + s6 + s7
+}
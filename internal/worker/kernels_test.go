@@ -0,0 +1,31 @@
+package worker
+
+import "testing"
+
+func TestMemoryKernelSize(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		want int // 每个数组的元素数
+	}{
+		{"默认", 0, DefaultMemoryKernelSize / 24},
+		{"按配置", 48 * 1024 * 1024, 2 << 20},
+		{"取整到整段", 25 * 1024 * 1024, 33 * memoryStreamStep},
+		{"至少一段", 1024, memoryStreamStep},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newMemoryKernel(kernelOptions{memorySize: tt.size}).(*memoryKernel)
+			if len(k.a) != tt.want || len(k.b) != tt.want || len(k.c) != tt.want {
+				t.Fatalf("数组长度 %d/%d/%d，期望 %d", len(k.a), len(k.b), len(k.c), tt.want)
+			}
+			// 跑满一轮后回到起点
+			for i := 0; i < tt.want/memoryStreamStep; i++ {
+				k.Run()
+			}
+			if k.offset != 0 {
+				t.Fatalf("一轮后 offset = %d，期望 0", k.offset)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package worker
+
+// Loader 负载工作器，新的资源类型实现此接口即可接入控制循环
+type Loader interface {
+	Start()
+	Stop()
+	IsRunning() bool
+	GetUsage() float64
+	AdjustLoad(currentWorkerUsage, targetWorkerUsage float64)
+}
+
+// Reporter 可选接口，提供界面显示用的状态摘要
+type Reporter interface {
+	// ShortStatus 控制台单行显示，例如 "I:30%"
+	ShortStatus() string
+	// Status 托盘菜单显示，例如 "强度:30%"
+	Status() string
+}
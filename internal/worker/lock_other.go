@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !windows
+
+package worker
+
+import "errors"
+
+// lockMemory 当前平台不支持锁定内存
+func lockMemory(b []byte) error {
+	return errors.New("当前平台不支持锁定内存")
+}
+
+func unlockMemory(b []byte) {}
@@ -0,0 +1,21 @@
+//go:build linux || darwin
+
+package worker
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// lockMemory 锁定内存块，禁止换出
+func lockMemory(b []byte) error {
+	if err := unix.Mlock(b); err != nil {
+		return fmt.Errorf("mlock 失败 (检查 RLIMIT_MEMLOCK 或 CAP_IPC_LOCK): %w", err)
+	}
+	return nil
+}
+
+func unlockMemory(b []byte) {
+	unix.Munlock(b)
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package worker
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockMemory 使用 VirtualLock 锁定内存块，受进程工作集大小限制
+func lockMemory(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if err := windows.VirtualLock(uintptr(unsafe.Pointer(&b[0])), uintptr(len(b))); err != nil {
+		return fmt.Errorf("VirtualLock 失败: %w", err)
+	}
+	return nil
+}
+
+func unlockMemory(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	windows.VirtualUnlock(uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)))
+}
@@ -1,10 +1,15 @@
 package worker
 
 import (
+	"fmt"
+	"log"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"MikaBooM/internal/pid"
+	"MikaBooM/internal/psi"
+
 	"github.com/shirou/gopsutil/v3/mem"
 )
 
@@ -15,12 +20,35 @@ type MemoryWorker struct {
 	allocatedMem   [][]byte
 	mu             sync.Mutex
 	stopChan       chan struct{}
+	wg             sync.WaitGroup
 	targetSize     atomic.Int64
 	totalMemory    int64
 	lastAdjustTime time.Time
 	adjustMutex    sync.Mutex
+	controller     *pid.Controller
+	residentBuf    []byte // mincore 页面状态向量缓冲区
+	alloc          allocator
+
+	fill          FillMode      // 内存块写入内容的方式
+	touchInterval time.Duration // 定期改写页面的间隔，0 为不改写
+	lock          bool          // 锁定内存块，禁止换出
+	lockFailed    bool          // 锁定失败后不再尝试，由 w.mu 保护
+	touching      atomic.Bool   // 是否有改写页面的协程在运行
+
+	// chunkMu 使改写页面与释放内存块互斥，保证不会写入已归还系统的内存
+	// 只在改写单个内存块期间持有，释放路径最多等待一个内存块；加锁顺序为 chunkMu -> mu
+	chunkMu sync.Mutex
+	freeGen uint64 // 每次释放内存块时递增，由 chunkMu 保护
+
+	pressure  PressureLimit // 内存压力上限，超过时立即释放
+	relieved  time.Time     // 最近一次因内存压力释放的时间，只在工作协程中访问
+	floor     uint64        // 系统可用内存下限，低于时立即释放全部，0 为不检查
+	holdUntil time.Time     // 紧急释放后暂停增加的截止时间，由 adjustMutex 保护
 }
 
+// pressureMinRelease 已分配内存不超过此值时紧急释放全部，否则释放一半
+const pressureMinRelease = 64 * 1024 * 1024
+
 func NewMemoryWorker(threshold int) *MemoryWorker {
 	// 动态获取系统总内存
 	totalMem := int64(16 * 1024 * 1024 * 1024) // 默认值 16GB
@@ -34,6 +62,8 @@ func NewMemoryWorker(threshold int) *MemoryWorker {
 		stopChan:       make(chan struct{}),
 		totalMemory:    totalMem,
 		lastAdjustTime: time.Now(),
+		controller:     pid.New(0.2, 0.2, 0, 0, 80),
+		alloc:          newAllocator(AllocHeap),
 	}
 	w.usage.Store(0.0)
 	w.targetSize.Store(0)
@@ -48,6 +78,16 @@ func (w *MemoryWorker) Start() {
 	w.running.Store(true)
 	w.stopChan = make(chan struct{})
 
+	w.mu.Lock()
+	w.lockFailed = false
+	w.mu.Unlock()
+
+	w.adjustMutex.Lock()
+	w.controller.Reset(0)
+	w.lastAdjustTime = time.Now()
+	w.adjustMutex.Unlock()
+
+	w.wg.Add(1)
 	go w.work()
 }
 
@@ -58,25 +98,68 @@ func (w *MemoryWorker) Stop() {
 
 	w.running.Store(false)
 	close(w.stopChan)
+	w.wg.Wait()
 
-	time.Sleep(100 * time.Millisecond)
-
+	w.chunkMu.Lock()
 	w.mu.Lock()
-	w.allocatedMem = make([][]byte, 0)
+	w.releaseAllLocked()
 	w.mu.Unlock()
+	w.chunkMu.Unlock()
 
 	w.usage.Store(0.0)
 	w.targetSize.Store(0)
 }
 
 func (w *MemoryWorker) work() {
+	defer w.wg.Done()
+
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
 
+	var pressureC <-chan time.Time
+	if w.pressure.enabled() {
+		pressureTicker := time.NewTicker(pressureCheckInterval)
+		defer pressureTicker.Stop()
+		pressureC = pressureTicker.C
+	}
+
+	var floorC <-chan time.Time
+	if w.floor > 0 {
+		floorTicker := time.NewTicker(pressureCheckInterval)
+		defer floorTicker.Stop()
+		floorC = floorTicker.C
+	}
+
+	var touchC <-chan time.Time
+	if w.touchInterval > 0 {
+		touchTicker := time.NewTicker(w.touchInterval)
+		defer touchTicker.Stop()
+		touchC = touchTicker.C
+	}
+	touchRNG := splitmix64(time.Now().UnixNano())
+
 	for {
 		select {
 		case <-w.stopChan:
 			return
+		case <-pressureC:
+			if !w.checkPressure() {
+				pressureC = nil
+			}
+		case <-floorC:
+			if !w.checkAvailable() {
+				floorC = nil
+			}
+		case <-touchC:
+			// 在独立协程中改写，避免阻塞紧急释放检查；上一轮未完成时跳过
+			if w.touching.CompareAndSwap(false, true) {
+				w.wg.Add(1)
+				go func() {
+					defer w.wg.Done()
+					defer w.touching.Store(false)
+					w.touchPages(&touchRNG)
+				}()
+			}
 		case <-ticker.C:
 			targetSize := w.targetSize.Load()
 			currentSize := w.getCurrentAllocatedSize()
@@ -92,59 +175,195 @@ func (w *MemoryWorker) work() {
 	}
 }
 
+// allocateMemory 逐块分配并写入内存，写入期间不持有 w.mu，
+// 新内存块在写入完成后才加入列表，其他路径看不到未完成的内存块
 func (w *MemoryWorker) allocateMemory(size int64) {
-	w.mu.Lock()
-	defer w.mu.Unlock()
-
 	chunkSize := int64(10 * 1024 * 1024) // 10MB 每块
-	chunks := size / chunkSize
 
-	for i := int64(0); i < chunks; i++ {
-		chunk := make([]byte, chunkSize)
+	for size > 0 && w.running.Load() {
+		n := chunkSize
+		if size < n {
+			n = size
+		}
+		chunk, err := w.alloc.alloc(int(n))
+		if err != nil {
+			log.Printf("内存工作器分配内存失败: %v", err)
+			return
+		}
 		// 写入数据以确保真实分配
-		for j := 0; j < len(chunk); j += 4096 {
-			chunk[j] = byte(j % 256)
+		fillChunk(chunk, w.fill)
+
+		w.mu.Lock()
+		if w.lock && !w.lockFailed {
+			if err := lockMemory(chunk); err != nil {
+				log.Printf("内存工作器锁定内存失败，不再锁定: %v", err)
+				w.lockFailed = true
+			}
 		}
 		w.allocatedMem = append(w.allocatedMem, chunk)
+		w.mu.Unlock()
+
+		size -= n
 	}
+}
 
-	remainder := size % chunkSize
-	if remainder > 0 {
-		chunk := make([]byte, remainder)
-		for j := 0; j < len(chunk); j += 4096 {
-			chunk[j] = byte(j % 256)
-		}
-		w.allocatedMem = append(w.allocatedMem, chunk)
+// checkPressure 内存压力超过上限时立即释放内存，不等待下一次调节，并在一段时间内不再增加
+// 返回 false 表示无法读取内存压力
+func (w *MemoryWorker) checkPressure() bool {
+	p, err := psi.Read("memory")
+	if err != nil {
+		log.Printf("无法读取内存压力，停止检查: %v", err)
+		return false
+	}
+	w.relievePressure(p, time.Now())
+	return true
+}
+
+// relievePressure 压力超过上限时释放一半内存
+// avg10 滞后于实际压力，上次释放后的 pressureSettle 内不再释放，避免一次压力尖峰释放掉几乎全部内存
+func (w *MemoryWorker) relievePressure(p psi.Pressure, now time.Time) {
+	if !w.pressure.exceeded(p) || now.Sub(w.relieved) < pressureSettle {
+		return
+	}
+
+	current := w.getCurrentAllocatedSize()
+	release := current / 2
+	if current <= pressureMinRelease {
+		release = current
+	}
+	if freed := w.releaseNow(release); freed > 0 {
+		w.relieved = now
+		log.Printf("内存压力过高 (some %.1f%%, full %.1f%%)，立即释放 %dMB", p.Some.Avg10, p.Full.Avg10, freed/1024/1024)
 	}
 }
 
+// checkAvailable 系统可用内存低于下限时立即释放全部内存，不论阈值如何
+// 返回 false 表示无法读取可用内存
+func (w *MemoryWorker) checkAvailable() bool {
+	v, err := mem.VirtualMemory()
+	if err != nil {
+		log.Printf("无法读取可用内存，停止检查: %v", err)
+		return false
+	}
+	if v.Available >= w.floor {
+		return true
+	}
+
+	if freed := w.releaseNow(w.getCurrentAllocatedSize()); freed > 0 {
+		log.Printf("系统可用内存 %dMB 低于下限 %dMB，立即释放 %dMB", v.Available/1024/1024, w.floor/1024/1024, freed/1024/1024)
+	}
+	return true
+}
+
+// releaseNow 立即释放 size 字节并归还系统，在一段时间内不再增加，返回实际释放的字节数
+func (w *MemoryWorker) releaseNow(size int64) int64 {
+	current := w.getCurrentAllocatedSize()
+	if size > 0 {
+		w.freeMemory(size)
+		w.alloc.flush()
+	}
+	remaining := w.getCurrentAllocatedSize()
+	w.targetSize.Store(remaining)
+
+	w.adjustMutex.Lock()
+	w.holdUntil = time.Now().Add(pressureHold)
+	if w.totalMemory > 0 {
+		w.controller.Reset(float64(remaining) / float64(w.totalMemory) * 100)
+	}
+	w.adjustMutex.Unlock()
+
+	return current - remaining
+}
+
+// freeMemory 从最后分配的内存块开始释放，直到释放量不小于 size
 func (w *MemoryWorker) freeMemory(size int64) {
+	w.chunkMu.Lock()
+	defer w.chunkMu.Unlock()
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
 	freed := int64(0)
-	newMem := make([][]byte, 0)
+	keep := len(w.allocatedMem)
+	for keep > 0 && freed < size {
+		keep--
+		freed += int64(len(w.allocatedMem[keep]))
+		w.freeChunkLocked(w.allocatedMem[keep])
+		w.allocatedMem[keep] = nil
+	}
 
-	for i := len(w.allocatedMem) - 1; i >= 0; i-- {
-		if freed >= size {
-			newMem = append([][]byte{w.allocatedMem[i]}, newMem...)
-		} else {
-			freed += int64(len(w.allocatedMem[i]))
-		}
+	w.allocatedMem = w.allocatedMem[:keep]
+}
+
+// releaseAllLocked 释放所有内存块，调用方持有 w.chunkMu 和 w.mu
+func (w *MemoryWorker) releaseAllLocked() {
+	for _, chunk := range w.allocatedMem {
+		w.freeChunkLocked(chunk)
+	}
+	w.allocatedMem = make([][]byte, 0)
+}
+
+// freeChunkLocked 解除锁定并释放一个内存块，调用方持有 w.chunkMu 和 w.mu
+func (w *MemoryWorker) freeChunkLocked(chunk []byte) {
+	w.freeGen++
+	if w.lock {
+		unlockMemory(chunk)
 	}
+	w.alloc.free(chunk)
+}
+
+// touchPages 改写所有内存块的页面，使长时间未访问的页面不被换出或压缩
+// 只在复制内存块列表时持有 w.mu，逐块改写，期间有内存块被释放时放弃本轮
+func (w *MemoryWorker) touchPages(rng *splitmix64) {
+	w.chunkMu.Lock()
+	w.mu.Lock()
+	chunks := append([][]byte(nil), w.allocatedMem...)
+	gen := w.freeGen
+	w.mu.Unlock()
+	w.chunkMu.Unlock()
 
-	w.allocatedMem = newMem
+	for _, chunk := range chunks {
+		if !w.running.Load() {
+			return
+		}
+		w.chunkMu.Lock()
+		if w.freeGen != gen {
+			w.chunkMu.Unlock()
+			return
+		}
+		touchChunk(chunk, rng)
+		w.chunkMu.Unlock()
+	}
 }
 
 func (w *MemoryWorker) getCurrentAllocatedSize() int64 {
 	w.mu.Lock()
 	defer w.mu.Unlock()
+	return w.allocatedSizeLocked()
+}
+
+// getResidentSize 统计已分配内存中实际驻留在物理内存中的字节数
+// 当前平台不支持时返回分配大小，第二个返回值为 false
+func (w *MemoryWorker) getResidentSize() (int64, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 
 	total := int64(0)
 	for _, chunk := range w.allocatedMem {
-		total += int64(len(chunk))
+		size, ok := residentSize(chunk, &w.residentBuf)
+		if !ok {
+			return w.allocatedSizeLocked(), false
+		}
+		total += size
 	}
 
+	return total, true
+}
+
+func (w *MemoryWorker) allocatedSizeLocked() int64 {
+	total := int64(0)
+	for _, chunk := range w.allocatedMem {
+		total += int64(len(chunk))
+	}
 	return total
 }
 
@@ -156,11 +375,20 @@ func (w *MemoryWorker) AdjustLoad(currentWorkerUsage, targetWorkerUsage float64)
 	w.adjustMutex.Lock()
 	defer w.adjustMutex.Unlock()
 
-	if time.Since(w.lastAdjustTime) < 2*time.Second {
+	elapsed := time.Since(w.lastAdjustTime)
+	if elapsed < 2*time.Second {
 		return
 	}
 
 	w.lastAdjustTime = time.Now()
+	if elapsed > maxControlStep {
+		elapsed = maxControlStep
+	}
+
+	// 紧急释放后的一段时间内保持当前分配
+	if w.lastAdjustTime.Before(w.holdUntil) {
+		return
+	}
 
 	totalMemory := w.totalMemory
 	if totalMemory == 0 {
@@ -173,7 +401,9 @@ func (w *MemoryWorker) AdjustLoad(currentWorkerUsage, targetWorkerUsage float64)
 		}
 	}
 
-	targetBytes := int64(float64(totalMemory) * targetWorkerUsage / 100.0)
+	// PID 控制器输出工作器应占用的内存百分比
+	output := w.controller.Update(targetWorkerUsage, currentWorkerUsage, elapsed)
+	targetBytes := int64(float64(totalMemory) * output / 100.0)
 
 	maxAdjust := int64(512 * 1024 * 1024) // 每次最大调整 512MB
 	currentBytes := w.getCurrentAllocatedSize()
@@ -197,18 +427,55 @@ func (w *MemoryWorker) AdjustLoad(currentWorkerUsage, targetWorkerUsage float64)
 	w.targetSize.Store(targetBytes)
 }
 
+// SetAllocator 设置内存块的分配方式，需在 Start 之前调用
+func (w *MemoryWorker) SetAllocator(mode AllocatorMode) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.allocatedMem) == 0 {
+		w.alloc = newAllocator(mode)
+	}
+}
+
+// SetResidency 设置内存块的写入方式、改写页面的间隔以及是否锁定，下次启动时生效
+func (w *MemoryWorker) SetResidency(fill FillMode, touchInterval time.Duration, lock bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.allocatedMem) == 0 {
+		w.fill = fill
+		w.touchInterval = touchInterval
+		w.lock = lock
+	}
+}
+
+// SetPressureLimit 设置内存压力上限，超过时立即释放内存，下次启动时生效
+func (w *MemoryWorker) SetPressureLimit(l PressureLimit) {
+	w.pressure = l
+}
+
+// SetAvailableFloor 设置系统可用内存下限（字节），低于时立即释放全部内存，下次启动时生效
+func (w *MemoryWorker) SetAvailableFloor(floor uint64) {
+	w.floor = floor
+}
+
+// SetPIDGains 设置内存调节控制器的增益
+func (w *MemoryWorker) SetPIDGains(kp, ki, kd float64) {
+	w.controller.SetGains(kp, ki, kd)
+}
+
+// GetUsage 获取工作器占系统内存的百分比
+// 支持的平台上按实际驻留大小计算，被换出或压缩的页面不计入
 func (w *MemoryWorker) GetUsage() float64 {
 	if !w.running.Load() {
 		return 0
 	}
 
-	allocatedSize := w.getCurrentAllocatedSize()
+	residentSize, _ := w.getResidentSize()
 
 	if w.totalMemory == 0 {
 		return 0
 	}
 
-	usage := float64(allocatedSize) / float64(w.totalMemory) * 100.0
+	usage := float64(residentSize) / float64(w.totalMemory) * 100.0
 
 	if usage > 100 {
 		usage = 100
@@ -225,6 +492,22 @@ func (w *MemoryWorker) GetAllocatedSize() int64 {
 	return w.getCurrentAllocatedSize()
 }
 
+// GetResidentSize 获取已分配内存中实际驻留的字节数
+func (w *MemoryWorker) GetResidentSize() int64 {
+	size, _ := w.getResidentSize()
+	return size
+}
+
+// ShortStatus 控制台显示的状态摘要
+func (w *MemoryWorker) ShortStatus() string {
+	return fmt.Sprintf("A:%dMB", w.GetAllocatedSize()/1024/1024)
+}
+
+// Status 托盘显示的状态摘要
+func (w *MemoryWorker) Status() string {
+	return fmt.Sprintf("已分配:%dMB, 目标:%dMB", w.GetAllocatedSize()/1024/1024, w.GetTargetSize()/1024/1024)
+}
+
 func (w *MemoryWorker) GetTargetSize() int64 {
 	return w.targetSize.Load()
 }
@@ -257,8 +540,10 @@ func (w *MemoryWorker) GetChunkCount() int {
 }
 
 func (w *MemoryWorker) ClearMemory() {
+	w.chunkMu.Lock()
+	defer w.chunkMu.Unlock()
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	w.allocatedMem = make([][]byte, 0)
+	w.releaseAllLocked()
 	w.targetSize.Store(0)
 }
\ No newline at end of file
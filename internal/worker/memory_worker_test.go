@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"MikaBooM/internal/psi"
+)
+
+func TestTouchPagesConcurrentWithFree(t *testing.T) {
+	for _, mode := range []AllocatorMode{AllocHeap, AllocMmap} {
+		w := NewMemoryWorker(50)
+		w.SetAllocator(mode)
+		w.running.Store(true)
+		w.allocateMemory(64 * 1024 * 1024)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := splitmix64(1)
+			for i := 0; i < 20; i++ {
+				w.touchPages(&rng)
+			}
+		}()
+		for w.GetAllocatedSize() > 0 {
+			w.freeMemory(10 * 1024 * 1024)
+		}
+		wg.Wait()
+
+		w.running.Store(false)
+		if n := w.GetChunkCount(); n != 0 {
+			t.Fatalf("mode %d: %d chunks left after freeing everything", mode, n)
+		}
+	}
+}
+
+func TestAllocateStopsWhenNotRunning(t *testing.T) {
+	w := NewMemoryWorker(50)
+	w.allocateMemory(64 * 1024 * 1024)
+	if size := w.GetAllocatedSize(); size != 0 {
+		t.Fatalf("allocated %d bytes while stopped", size)
+	}
+}
+
+func TestRelievePressureWaitsForAvg10(t *testing.T) {
+	w := NewMemoryWorker(50)
+	w.SetPressureLimit(PressureLimit{Some: 10})
+	w.running.Store(true)
+	w.allocateMemory(512 * 1024 * 1024)
+	defer func() {
+		w.running.Store(false)
+		w.freeMemory(w.GetAllocatedSize())
+	}()
+
+	high := psi.Pressure{Some: psi.Stall{Avg10: 40}}
+	now := time.Now()
+	before := w.GetAllocatedSize()
+	w.relievePressure(high, now)
+	after := w.GetAllocatedSize()
+	if after >= before || after == 0 {
+		t.Fatalf("allocated %dMB -> %dMB after first release, want about half", before/1024/1024, after/1024/1024)
+	}
+
+	// avg10 还没有反映释放的效果，窗口内的后续检查不再释放
+	for i := 1; i < 20; i++ {
+		w.relievePressure(high, now.Add(time.Duration(i)*pressureCheckInterval))
+	}
+	if got := w.GetAllocatedSize(); got != after {
+		t.Fatalf("released again within the avg10 window: %dMB left", got/1024/1024)
+	}
+
+	w.relievePressure(high, now.Add(pressureSettle))
+	if got := w.GetAllocatedSize(); got >= after {
+		t.Fatalf("allocated %dMB after the window, want another release", got/1024/1024)
+	}
+}
@@ -0,0 +1,281 @@
+package worker
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type NetworkWorker struct {
+	threshold int
+	endpoint  string
+	maxMbps   float64
+	running   atomic.Bool
+	usage     atomic.Value // float64
+	rate      atomic.Int64 // 目标发送速率（字节/秒）
+	sent      atomic.Int64 // 累计发送字节数
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+
+	sampleMutex    sync.Mutex
+	lastSampleTime time.Time
+	lastSampleSent int64
+}
+
+// NewNetworkWorker 创建网络工作器，流量发送到 endpoint (host:port, TCP)
+func NewNetworkWorker(threshold int, endpoint string, maxMbps int) *NetworkWorker {
+	w := &NetworkWorker{
+		threshold: threshold,
+		endpoint:  endpoint,
+		maxMbps:   float64(maxMbps),
+		stopChan:  make(chan struct{}),
+	}
+	w.usage.Store(0.0)
+	return w
+}
+
+func (w *NetworkWorker) Start() {
+	if w.running.Load() {
+		return
+	}
+
+	w.running.Store(true)
+	w.stopChan = make(chan struct{})
+
+	w.sampleMutex.Lock()
+	w.lastSampleTime = time.Now()
+	w.lastSampleSent = w.sent.Load()
+	w.sampleMutex.Unlock()
+
+	w.wg.Add(1)
+	go w.work()
+}
+
+func (w *NetworkWorker) Stop() {
+	if !w.running.Load() {
+		return
+	}
+
+	w.running.Store(false)
+	close(w.stopChan)
+	w.wg.Wait()
+	w.usage.Store(0.0)
+	w.rate.Store(0)
+}
+
+func (w *NetworkWorker) work() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	buf := make([]byte, 64*1024)
+	for i := range buf {
+		buf[i] = byte(i * 31)
+	}
+
+	var conn net.Conn
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	budget := 0.0
+	last := time.Now()
+	nextDial := time.Time{}
+
+	for {
+		select {
+		case <-w.stopChan:
+			return
+		case now := <-ticker.C:
+			rate := float64(w.rate.Load())
+			budget += rate * now.Sub(last).Seconds()
+			last = now
+
+			// 最多累积 100ms 的发送额度，避免断线重连后突发
+			if budget > rate/10 {
+				budget = rate / 10
+			}
+			if budget < 1 {
+				continue
+			}
+
+			if conn == nil {
+				if now.Before(nextDial) {
+					budget = 0
+					continue
+				}
+				c, err := net.DialTimeout("tcp", w.endpoint, 3*time.Second)
+				if err != nil {
+					log.Printf("网络工作器连接 %s 失败: %v", w.endpoint, err)
+					nextDial = now.Add(5 * time.Second)
+					budget = 0
+					continue
+				}
+				conn = c
+			}
+
+			for budget >= 1 {
+				n := int(budget)
+				if n > len(buf) {
+					n = len(buf)
+				}
+				conn.SetWriteDeadline(time.Now().Add(time.Second))
+				written, err := conn.Write(buf[:n])
+				w.sent.Add(int64(written))
+				budget -= float64(written)
+				if err != nil {
+					conn.Close()
+					conn = nil
+					break
+				}
+			}
+		}
+	}
+}
+
+func (w *NetworkWorker) AdjustLoad(currentWorkerUsage, targetWorkerUsage float64) {
+	if !w.running.Load() {
+		return
+	}
+
+	// 发送速率由令牌桶精确限制，直接按目标带宽设置
+	target := targetWorkerUsage
+	if target < 0 {
+		target = 0
+	}
+	if w.maxMbps > 0 && target > w.maxMbps {
+		target = w.maxMbps
+	}
+
+	w.rate.Store(int64(target * 1000 * 1000 / 8))
+}
+
+// GetUsage 获取工作器实际发送带宽（Mbps）
+func (w *NetworkWorker) GetUsage() float64 {
+	if !w.running.Load() {
+		return 0
+	}
+
+	w.sampleMutex.Lock()
+	defer w.sampleMutex.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(w.lastSampleTime)
+	if elapsed < minSampleInterval {
+		return w.usage.Load().(float64)
+	}
+
+	total := w.sent.Load()
+	usage := float64(total-w.lastSampleSent) * 8 / elapsed.Seconds() / 1000 / 1000
+	w.lastSampleTime = now
+	w.lastSampleSent = total
+
+	w.usage.Store(usage)
+	return usage
+}
+
+func (w *NetworkWorker) IsRunning() bool {
+	return w.running.Load()
+}
+
+// GetRate 获取目标发送速率（Mbps）
+func (w *NetworkWorker) GetRate() float64 {
+	return float64(w.rate.Load()) * 8 / 1000 / 1000
+}
+
+// GetSentBytes 获取累计发送字节数
+func (w *NetworkWorker) GetSentBytes() int64 {
+	return w.sent.Load()
+}
+
+// GetEndpoint 获取流量目标地址
+func (w *NetworkWorker) GetEndpoint() string {
+	return w.endpoint
+}
+
+// ShortStatus 控制台显示的状态摘要
+func (w *NetworkWorker) ShortStatus() string {
+	return fmt.Sprintf("R:%.1fMbps", w.GetRate())
+}
+
+// Status 托盘显示的状态摘要
+func (w *NetworkWorker) Status() string {
+	return fmt.Sprintf("速率:%.1fMbps, 目标:%s", w.GetRate(), w.endpoint)
+}
+
+// NetworkSink 本地流量接收端，丢弃收到的所有数据，用于测试或自发自收
+type NetworkSink struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	conns    map[net.Conn]struct{}
+}
+
+// StartNetworkSink 在 addr 上启动接收端
+func StartNetworkSink(addr string) (*NetworkSink, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("启动网络接收端失败: %w", err)
+	}
+
+	s := &NetworkSink{
+		listener: listener,
+		conns:    make(map[net.Conn]struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.accept()
+	return s, nil
+}
+
+func (s *NetworkSink) accept() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			io.Copy(io.Discard, conn)
+			conn.Close()
+
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// Addr 返回接收端实际监听的地址
+func (s *NetworkSink) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close 关闭接收端及所有连接
+func (s *NetworkSink) Close() error {
+	err := s.listener.Close()
+
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
@@ -0,0 +1,27 @@
+package worker
+
+import (
+	"time"
+
+	"MikaBooM/internal/psi"
+)
+
+const (
+	pressureCheckInterval = 500 * time.Millisecond // 检查 PSI 的间隔
+	pressureHold          = 30 * time.Second       // 紧急退让后暂停增加负载的时长
+	pressureSettle        = 10 * time.Second       // avg10 的统计窗口，退让后等待这么久，让压力反映退让的效果后再决定是否继续
+)
+
+// PressureLimit PSI 停顿上限（最近 10 秒的百分比），0 为不检查
+type PressureLimit struct {
+	Some float64
+	Full float64
+}
+
+func (l PressureLimit) enabled() bool {
+	return l.Some > 0 || l.Full > 0
+}
+
+func (l PressureLimit) exceeded(p psi.Pressure) bool {
+	return (l.Some > 0 && p.Some.Avg10 >= l.Some) || (l.Full > 0 && p.Full.Avg10 >= l.Full)
+}
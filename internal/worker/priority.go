@@ -0,0 +1,21 @@
+package worker
+
+// SchedPolicy 工作线程的 CPU 调度策略
+type SchedPolicy int
+
+const (
+	SchedNormal SchedPolicy = iota // 普通优先级
+	SchedNice                      // nice 19
+	SchedIdle                      // SCHED_IDLE，只在 CPU 空闲时运行
+)
+
+// Priority 工作线程的调度优先级，真实负载出现时立即让出 CPU 和磁盘
+type Priority struct {
+	Policy SchedPolicy
+	IOIdle bool // I/O 优先级设为 idle，只使用空闲的磁盘带宽
+}
+
+// lowered 是否需要调整线程优先级
+func (p Priority) lowered() bool {
+	return p.Policy != SchedNormal || p.IOIdle
+}
@@ -0,0 +1,44 @@
+//go:build linux
+
+package worker
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ioprioWhoProcess = 1
+	ioprioClassIdle  = 3
+	ioprioClassShift = 13
+)
+
+// lowerThreadPriority 降低调用线程的 CPU 和 I/O 优先级
+// 调用方必须已通过 runtime.LockOSThread 绑定线程，且在线程退出前保持绑定，
+// 否则降低了优先级的线程会回到运行时的线程池中执行其他 goroutine
+func lowerThreadPriority(p Priority) error {
+	switch p.Policy {
+	case SchedIdle:
+		attr := unix.SchedAttr{
+			Size:   unix.SizeofSchedAttr,
+			Policy: unix.SCHED_IDLE,
+			Nice:   19,
+		}
+		if err := unix.SchedSetAttr(0, &attr, 0); err != nil {
+			return fmt.Errorf("设置 SCHED_IDLE 失败: %w", err)
+		}
+	case SchedNice:
+		if err := unix.Setpriority(unix.PRIO_PROCESS, unix.Gettid(), 19); err != nil {
+			return fmt.Errorf("设置 nice 19 失败: %w", err)
+		}
+	}
+
+	if p.IOIdle {
+		_, _, errno := unix.Syscall(unix.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(unix.Gettid()), ioprioClassIdle<<ioprioClassShift)
+		if errno != 0 {
+			return fmt.Errorf("设置 I/O 优先级失败: %w", errno)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package worker
+
+import "errors"
+
+// lowerThreadPriority 当前平台不支持按线程调整优先级，工作线程保持普通优先级
+func lowerThreadPriority(p Priority) error {
+	return errors.New("当前平台不支持调整工作线程优先级")
+}
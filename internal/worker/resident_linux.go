@@ -0,0 +1,50 @@
+//go:build linux
+
+package worker
+
+import (
+	"os"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// residentSize 使用 mincore 统计 b 中实际驻留在物理内存中的字节数
+// 被换出（包括换出到 zram）的页面不计入，buf 用于复用页面状态向量
+func residentSize(b []byte, buf *[]byte) (int64, bool) {
+	if len(b) == 0 {
+		return 0, true
+	}
+
+	pageSize := uintptr(os.Getpagesize())
+	addr := uintptr(unsafe.Pointer(&b[0]))
+	start := addr &^ (pageSize - 1)
+	end := addr + uintptr(len(b))
+	pages := int((end - start + pageSize - 1) / pageSize)
+
+	if cap(*buf) < pages {
+		*buf = make([]byte, pages)
+	}
+	vec := (*buf)[:pages]
+
+	_, _, errno := unix.Syscall(unix.SYS_MINCORE, start, end-start, uintptr(unsafe.Pointer(&vec[0])))
+	runtime.KeepAlive(b)
+	if errno != 0 {
+		return 0, false
+	}
+
+	resident := int64(0)
+	for _, v := range vec {
+		if v&1 != 0 {
+			resident += int64(pageSize)
+		}
+	}
+
+	// 首尾页面可能只有一部分属于 b
+	if resident > int64(len(b)) {
+		resident = int64(len(b))
+	}
+
+	return resident, true
+}
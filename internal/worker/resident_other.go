@@ -0,0 +1,8 @@
+//go:build !linux
+
+package worker
+
+// residentSize 当前平台不支持查询页面驻留状态，由调用方回退到分配大小
+func residentSize(b []byte, buf *[]byte) (int64, bool) {
+	return int64(len(b)), false
+}
@@ -0,0 +1,21 @@
+package worker
+
+import "golang.org/x/sys/cpu"
+
+// hasAVX CPU 和操作系统都支持 AVX（操作系统需要保存 YMM 寄存器）
+var hasAVX = cpu.X86.HasAVX
+
+// saxpyDot 计算 z = alpha*x + y 并返回 z 与 x 的点积，只处理前 32 的整数倍个元素
+func saxpyDot(alpha float32, x, y, z []float32) float32 {
+	n := len(z) &^ 31
+	x, y, z = x[:n], y[:n], z[:n]
+	if hasAVX {
+		return saxpyDotAVX(alpha, x, y, z)
+	}
+	return saxpyDotGeneric(alpha, x, y, z)
+}
+
+// saxpyDotAVX 使用 AVX 实现的 saxpyDot，x、y、z 的长度相同且为 32 的整数倍
+//
+//go:noescape
+func saxpyDotAVX(alpha float32, x, y, z []float32) float32
@@ -0,0 +1,64 @@
+#include "textflag.h"
+
+// func saxpyDotAVX(alpha float32, x, y, z []float32) float32
+// 每次处理 32 个元素（4 个 YMM 寄存器），四组累加器互不依赖
+TEXT ·saxpyDotAVX(SB), NOSPLIT, $0-84
+	MOVQ x_base+8(FP), SI
+	MOVQ y_base+32(FP), DI
+	MOVQ z_base+56(FP), DX
+	MOVQ z_len+64(FP), CX
+	VBROADCASTSS alpha+0(FP), Y0
+	VXORPS Y8, Y8, Y8
+	VXORPS Y9, Y9, Y9
+	VXORPS Y10, Y10, Y10
+	VXORPS Y11, Y11, Y11
+	SHRQ $5, CX
+	JZ   done
+
+loop:
+	// z = alpha*x + y
+	VMOVUPS (SI), Y4
+	VMOVUPS 32(SI), Y5
+	VMOVUPS 64(SI), Y6
+	VMOVUPS 96(SI), Y7
+	VMULPS  Y4, Y0, Y1
+	VMULPS  Y5, Y0, Y2
+	VMULPS  Y6, Y0, Y3
+	VMULPS  Y7, Y0, Y12
+	VADDPS  (DI), Y1, Y1
+	VADDPS  32(DI), Y2, Y2
+	VADDPS  64(DI), Y3, Y3
+	VADDPS  96(DI), Y12, Y12
+	VMOVUPS Y1, (DX)
+	VMOVUPS Y2, 32(DX)
+	VMOVUPS Y3, 64(DX)
+	VMOVUPS Y12, 96(DX)
+
+	// sum += z*x
+	VMULPS Y4, Y1, Y1
+	VMULPS Y5, Y2, Y2
+	VMULPS Y6, Y3, Y3
+	VMULPS Y7, Y12, Y12
+	VADDPS Y1, Y8, Y8
+	VADDPS Y2, Y9, Y9
+	VADDPS Y3, Y10, Y10
+	VADDPS Y12, Y11, Y11
+
+	ADDQ $128, SI
+	ADDQ $128, DI
+	ADDQ $128, DX
+	DECQ CX
+	JNZ  loop
+
+done:
+	// 合并累加器并横向求和
+	VADDPS       Y9, Y8, Y8
+	VADDPS       Y11, Y10, Y10
+	VADDPS       Y10, Y8, Y8
+	VEXTRACTF128 $1, Y8, X1
+	VADDPS       X1, X8, X8
+	VHADDPS      X8, X8, X8
+	VHADDPS      X8, X8, X8
+	VZEROUPPER
+	MOVSS        X8, ret+80(FP)
+	RET
@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSaxpyDotAVXMatchesGeneric(t *testing.T) {
+	if !hasAVX {
+		t.Skip("CPU 不支持 AVX")
+	}
+	for _, n := range []int{0, 32, 96, vectorLen, vectorLen + 7} {
+		x, y := make([]float32, n), make([]float32, n)
+		for i := range x {
+			x[i] = float32(i%97) * 0.01
+			y[i] = float32(i%89) * 0.02
+		}
+		z1, z2 := make([]float32, n), make([]float32, n)
+
+		got := saxpyDotAVX(1.0001, x[:n&^31], y[:n&^31], z1[:n&^31])
+		want := saxpyDotGeneric(1.0001, x[:n&^31], y[:n&^31], z2[:n&^31])
+
+		// 加法顺序不同，点积只比较相对误差；逐元素的 saxpy 结果应完全相同
+		if math.Abs(float64(got-want)) > 1e-5*math.Max(1, math.Abs(float64(want))) {
+			t.Errorf("n=%d: AVX %v，标量 %v", n, got, want)
+		}
+		for i := range z1 {
+			if z1[i] != z2[i] {
+				t.Fatalf("n=%d: z[%d] AVX %v，标量 %v", n, i, z1[i], z2[i])
+			}
+		}
+	}
+}
+
+// BenchmarkVectorKernel 对比 AVX 与标量实现，每次迭代为一次 Run
+func BenchmarkVectorKernel(b *testing.B) {
+	run := func(b *testing.B, avx bool) {
+		defer func(v bool) { hasAVX = v }(hasAVX)
+		hasAVX = avx
+		k := newVectorKernel(kernelOptions{})
+		for i := 0; i < b.N; i++ {
+			k.Run()
+		}
+	}
+	b.Run("generic", func(b *testing.B) { run(b, false) })
+	if hasAVX {
+		b.Run("avx", func(b *testing.B) { run(b, true) })
+	}
+}
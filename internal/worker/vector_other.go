@@ -0,0 +1,9 @@
+//go:build !amd64
+
+package worker
+
+// saxpyDot 计算 z = alpha*x + y 并返回 z 与 x 的点积，只处理前 32 的整数倍个元素
+func saxpyDot(alpha float32, x, y, z []float32) float32 {
+	n := len(z) &^ 31
+	return saxpyDotGeneric(alpha, x[:n], y[:n], z[:n])
+}
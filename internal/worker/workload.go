@@ -0,0 +1,41 @@
+package worker
+
+// Workload CPU 工作线程执行的计算类型
+type Workload int
+
+const (
+	WorkloadMix      Workload = iota // 圆周率、三角函数、矩阵、对数等混合运算
+	WorkloadInteger                  // 整数运算和位运算
+	WorkloadFloat                    // 浮点乘加、除法和开方
+	WorkloadSHA256                   // SHA-256 哈希
+	WorkloadCompress                 // compress/flate 压缩
+	WorkloadMemory                   // 顺序读写大数组，占用内存带宽
+	WorkloadVector                   // 单精度向量运算，支持时使用 AVX 指令
+)
+
+// Kernel 一种 CPU 计算负载
+// Run 执行一小段计算（约数十微秒），工作线程反复调用直到本周期的工作时间用完
+// 每个工作线程使用自己的 Kernel，实现无需考虑并发
+type Kernel interface {
+	Run()
+}
+
+// kernelOptions 创建 Kernel 时使用的设置
+type kernelOptions struct {
+	memorySize int // memory 计算类型每个工作线程使用的内存（字节），0 为默认值
+}
+
+var kernels = make(map[Workload]func(kernelOptions) Kernel)
+
+// registerKernel 登记计算类型对应的 Kernel 构造函数
+func registerKernel(w Workload, newKernel func(kernelOptions) Kernel) {
+	kernels[w] = newKernel
+}
+
+// newKernel 创建计算类型对应的 Kernel，未登记的类型使用混合运算
+func newKernel(w Workload, opts kernelOptions) Kernel {
+	if f, ok := kernels[w]; ok {
+		return f(opts)
+	}
+	return kernels[WorkloadMix](opts)
+}
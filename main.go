@@ -1,21 +1,34 @@
 package main
 
 import (
+	"MikaBooM/internal/api"
 	"MikaBooM/internal/autostart"
+	"MikaBooM/internal/cgroup"
+	"MikaBooM/internal/compliance"
 	"MikaBooM/internal/config"
+	"MikaBooM/internal/controller"
+	"MikaBooM/internal/history"
+	"MikaBooM/internal/logger"
+	"MikaBooM/internal/metrics"
 	"MikaBooM/internal/monitor"
 	"MikaBooM/internal/notify"
+	"MikaBooM/internal/resource"
+	"MikaBooM/internal/schedule"
 	"MikaBooM/internal/sysinfo"
 	"MikaBooM/internal/tray"
+	"MikaBooM/internal/tui"
 	"MikaBooM/internal/updater"
 	"MikaBooM/internal/version"
 	"MikaBooM/internal/worker"
 	"flag"
 	"fmt"
-	"io"
 	"log"
+	"log/slog"
+	"math"
 	"os"
 	"os/signal"
+	"reflect"
+	"strings"
 	"syscall"
 	"time"
 
@@ -32,6 +45,7 @@ var (
 	showHelp         = flag.Bool("h", false, "显示帮助信息")
 	configFile       = flag.String("c", "", "指定配置文件路径")
 	checkUpdate      = flag.Bool("update", false, "检查并更新到最新版本")
+	tuiMode          = flag.Bool("tui", false, "全屏终端仪表盘")
 )
 
 func main() {
@@ -53,6 +67,15 @@ func main() {
 		return
 	}
 
+	// 子命令连接已经运行的实例，不启动新的监控循环
+	if flag.NArg() > 0 {
+		if err := runCommand(flag.Args()); err != nil {
+			color.Red("✗ %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// 处理更新
 	if *checkUpdate {
 		handleUpdate()
@@ -89,28 +112,7 @@ func main() {
 		}
 	}
 
-	if *cpuThreshold >= 0 {
-		cfg.CPUThreshold = *cpuThreshold
-		color.Yellow("⚙️  命令行参数覆盖: CPU阈值 = %d%%", cfg.CPUThreshold)
-	}
-
-	if *memThreshold >= 0 {
-		cfg.MemoryThreshold = *memThreshold
-		color.Yellow("⚙️  命令行参数覆盖: 内存阈值 = %d%%", cfg.MemoryThreshold)
-	}
-
-	if *showWindow != "" {
-		switch *showWindow {
-		case "true", "1", "yes", "on":
-			cfg.ShowWindow = true
-			color.Yellow("⚙️  命令行参数覆盖: 显示窗口 = true")
-		case "false", "0", "no", "off":
-			cfg.ShowWindow = false
-			color.Yellow("⚙️  命令行参数覆盖: 显示窗口 = false")
-		default:
-			color.Yellow("⚠ 无效的 -window 参数值: %s (使用 true 或 false)", *showWindow)
-		}
-	}
+	applyFlagOverrides(cfg, true)
 
 	if *enableAutoStart {
 		if err := autostart.Enable(); err != nil {
@@ -156,9 +158,31 @@ func main() {
 		}
 	}
 
-	if !cfg.ShowWindow {
-		log.SetOutput(io.Discard)
+	// 日志写入轮转文件；前台运行时各模块通过 log 包输出的诊断信息同时显示在控制台，
+	// 本文件的 slog 记录在控制台上已有对应的彩色信息，只写入文件
+	logOpts := logger.Options{
+		Level:      cfg.Log.Level,
+		Format:     cfg.Log.Format,
+		MaxSizeMB:  cfg.Log.MaxSizeMB,
+		MaxFiles:   cfg.Log.MaxFiles,
+		MaxAgeDays: cfg.Log.MaxAgeDays,
+	}
+	if cfg.Log.Enabled {
+		logOpts.Path = logPath(cfg, cfgPath)
+	}
+	if cfg.ShowWindow {
+		logOpts.Console = os.Stderr
+	}
+	closeLog, err := logger.Setup(logOpts)
+	if err != nil {
+		if cfg.ShowWindow {
+			color.Yellow("⚠ %v，日志不写入文件", err)
+		}
+		logOpts.Path = ""
+		closeLog, _ = logger.Setup(logOpts)
 	}
+	defer closeLog()
+	slog.Info("程序启动", "version", version.GetVersion(), "config", cfgPath, "log", logOpts.Path)
 
 	if cfg.ShowWindow {
 		fmt.Println()
@@ -185,23 +209,159 @@ func main() {
 
 	cpuMonitor := monitor.NewCPUMonitor()
 	memMonitor := monitor.NewMemoryMonitor()
+	useCgroup(cfg, cpuMonitor, memMonitor)
+
+	var limiter *cgroupLimiter
+	if cfg.Cgroup.Confine && versionValid {
+		limiter = confineCgroup(cfg, cpuMonitor, memMonitor)
+	}
+	if cfg.Memory.OOMScoreAdj != 0 && versionValid {
+		if err := sysinfo.SetOOMScoreAdj(cfg.Memory.OOMScoreAdj); err != nil {
+			slog.Warn("无法设置 OOM 优先级", "error", err)
+			if cfg.ShowWindow {
+				color.Yellow("⚠ %v", err)
+			}
+		}
+	}
 	notifier := notify.NewNotifier(cfg.Notification.Enabled, cfg.Notification.Cooldown)
 
-	var cpuWorker *worker.CPUWorker
-	var memWorker *worker.MemoryWorker
+	cpuRes := &resource.Resource{
+		Name:      resource.CPU,
+		Tag:       "CPU",
+		Label:     "CPU",
+		Unit:      "%",
+		Source:    cpuMonitor,
+		MaxTarget: 100,
+		Threshold: func(c *config.Config) int { return c.CPUThreshold },
+	}
+	memRes := &resource.Resource{
+		Name:      resource.Memory,
+		Tag:       "MEM",
+		Label:     "内存",
+		Unit:      "%",
+		Source:    memMonitor,
+		MaxTarget: 80, // 最多使用80%系统内存
+		Threshold: func(c *config.Config) int { return c.MemoryThreshold },
+	}
 
 	if versionValid {
-		cpuWorker = worker.NewCPUWorker(cfg.CPUThreshold)
-		memWorker = worker.NewMemoryWorker(cfg.MemoryThreshold)
+		cpuWorker := worker.NewCPUWorker(cfg.CPUThreshold)
+		memWorker := worker.NewMemoryWorker(cfg.MemoryThreshold)
+		cpuWorker.SetPIDGains(cfg.Control.CPU.Kp, cfg.Control.CPU.Ki, cfg.Control.CPU.Kd)
+		memWorker.SetPIDGains(cfg.Control.Memory.Kp, cfg.Control.Memory.Ki, cfg.Control.Memory.Kd)
+		if cfg.CPU.PerCore {
+			cpuWorker.SetPerCore(cpuMonitor.GetCachedPerCoreUsage)
+		}
+		cpuWorker.SetPriority(workerPriority(cfg))
+		cpuWorker.SetWorkload(cpuWorkloads[cfg.CPU.Workload])
+		cpuWorker.SetMemoryKernelSize(cfg.CPU.MemoryKernelMB * 1024 * 1024)
+		cpuWorker.SetCapacity(cpuMonitor.GetCapacity())
+		cpuWorker.SetPressureLimit(worker.PressureLimit{Some: cfg.Pressure.CPUSome})
+		if cfg.Memory.Allocator == config.AllocatorMmap {
+			memWorker.SetAllocator(worker.AllocMmap)
+		}
+		fill := worker.FillSparse
+		if cfg.Memory.Fill == config.FillRandom {
+			fill = worker.FillRandom
+		}
+		memWorker.SetResidency(fill, time.Duration(cfg.Memory.TouchInterval)*time.Second, cfg.Memory.Mlock)
+		memWorker.SetAvailableFloor(uint64(cfg.Memory.MinAvailable) * 1024 * 1024)
+		memWorker.SetPressureLimit(worker.PressureLimit{Some: cfg.Pressure.MemorySome, Full: cfg.Pressure.MemoryFull})
 
 		totalMem, err := memMonitor.GetTotalMemory()
 		if err == nil {
 			memWorker.SetTotalMemory(totalMem)
 		}
+
+		cpuRes.Loader = cpuWorker
+		memRes.Loader = memWorker
+	}
+
+	resources := []*resource.Resource{cpuRes, memRes}
+
+	// 网络负载默认关闭，设置了网络阈值或网络 p95 下限才注册
+	var netSink *worker.NetworkSink
+	if cfg.NetworkEnabled() {
+		endpoint := cfg.Network.Endpoint
+		if cfg.Network.Sink {
+			netSink, err = worker.StartNetworkSink(cfg.Network.SinkListen)
+			if err != nil {
+				color.Red("✗ %v", err)
+				log.Fatalf("%v", err)
+			}
+			defer netSink.Close()
+			if endpoint == "" {
+				endpoint = netSink.Addr()
+				if cfg.Network.Interface == "" {
+					// 发往本地接收端的流量只经过回环网卡，默认统计不包含回环网卡
+					slog.Warn("本地接收端的流量不计入网络占用，仅适合测试；如需统计请将 network.interface 设为回环网卡", "listen", netSink.Addr())
+					if cfg.ShowWindow {
+						color.Yellow("⚠ 本地接收端的流量不计入网络占用，仅适合测试；如需统计请将 network.interface 设为回环网卡")
+					}
+				}
+			}
+		}
+
+		netRes := &resource.Resource{
+			Name:      resource.Network,
+			Tag:       "NET",
+			Label:     "网络",
+			Unit:      "Mbps",
+			Source:    monitor.NewNetworkMonitor(cfg.Network.Interface),
+			MaxTarget: float64(cfg.Network.MaxMbps),
+			Threshold: func(c *config.Config) int { return c.NetworkThreshold },
+		}
+		if versionValid {
+			netRes.Loader = worker.NewNetworkWorker(cfg.NetworkThreshold, endpoint, cfg.Network.MaxMbps)
+		}
+		resources = append(resources, netRes)
+	}
+
+	if cfg.DiskEnabled() {
+		diskRes := &resource.Resource{
+			Name:      resource.Disk,
+			Tag:       "DISK",
+			Label:     "磁盘",
+			Unit:      "MB/s",
+			Source:    monitor.NewDiskMonitor(cfg.Disk.Devices),
+			MaxTarget: float64(cfg.Disk.MaxMBps),
+			Threshold: func(c *config.Config) int { return c.DiskThreshold },
+		}
+		if versionValid {
+			diskWorker := worker.NewDiskWorker(cfg.DiskThreshold, cfg.Disk.Directory, cfg.Disk.FileSizeMB, cfg.Disk.DirectIO, cfg.Disk.MaxMBps)
+			diskWorker.SetPriority(workerPriority(cfg))
+			diskRes.Loader = diskWorker
+		}
+		resources = append(resources, diskRes)
+	}
+
+	registry := resource.NewRegistry()
+	for _, res := range resources {
+		if err := registry.Register(res); err != nil {
+			color.Red("✗ 注册资源失败: %v", err)
+			log.Fatalf("注册资源失败: %v", err)
+		}
 	}
 
+	policies, tracker, err := buildPolicies(cfg, cfgPath, nil)
+	if err != nil {
+		color.Red("✗ %v", err)
+		log.Fatalf("%v", err)
+	}
+	defer func() {
+		if tracker != nil {
+			tracker.Save()
+		}
+	}()
+
+	ctrl := controller.New(cfg, controller.Options{
+		Registry: registry,
+		Notifier: notifier,
+		Policies: policies,
+	})
+
 	go func() {
-		tray.Start(cfg, cpuMonitor, memMonitor, cpuWorker, memWorker)
+		tray.Start(cfg, ctrl)
 	}()
 
 	ticker := time.NewTicker(time.Duration(cfg.UpdateInterval) * time.Second)
@@ -212,6 +372,80 @@ func main() {
 
 	trayQuitChan := tray.GetQuitChannel()
 
+	// 配置文件变化时自动重新加载，无法监视时仍可通过 SIGHUP 触发
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+
+	if cfg.API.Enabled {
+		apiServer, err := api.Listen(cfg.API.Listen, api.Options{
+			Controller: ctrl,
+			Token:      cfg.API.Token,
+			Quit:       tray.RequestQuit,
+		})
+		if err != nil {
+			log.Printf("%v", err)
+			if cfg.ShowWindow {
+				color.Yellow("⚠ %v", err)
+			}
+		} else {
+			defer apiServer.Close()
+			if cfg.ShowWindow {
+				color.Cyan("🔌 控制接口: %s", apiServer.Addr())
+			}
+		}
+	}
+
+	var collector *metrics.Collector
+	if cfg.Metrics.Enabled {
+		collector = metrics.NewCollector(ctrl)
+		metricsServer, err := metrics.Listen(cfg.Metrics.Listen, cfg.Metrics.Path, collector)
+		if err != nil {
+			log.Printf("%v", err)
+			if cfg.ShowWindow {
+				color.Yellow("⚠ %v", err)
+			}
+		} else {
+			defer metricsServer.Close()
+			if cfg.ShowWindow {
+				color.Cyan("📈 指标服务: http://%s%s", metricsServer.Addr(), cfg.Metrics.Path)
+			}
+		}
+	}
+
+	var recorder *history.Recorder
+	if cfg.History.Enabled {
+		units := make(map[string]string)
+		for _, res := range registry.All() {
+			units[res.Name] = res.Unit
+		}
+
+		path := historyPath(cfg, cfgPath)
+		recorder, err = history.NewRecorder(path, time.Duration(cfg.History.Interval)*time.Second, cfg.History.MaxSizeMB, cfg.History.MaxFiles, units)
+		if err != nil {
+			log.Printf("%v", err)
+			if cfg.ShowWindow {
+				color.Yellow("⚠ %v", err)
+			}
+		} else {
+			recorder.SetCapacity(resource.CPU, cpuMonitor.GetCapacity)
+			defer recorder.Close()
+			if cfg.ShowWindow {
+				color.Cyan("🗂️  历史记录: %s", path)
+			}
+		}
+	}
+
+	var reloadChan <-chan struct{}
+	watcher, err := config.NewWatcher(cfgPath)
+	if err != nil {
+		if cfg.ShowWindow {
+			color.Yellow("⚠ %v，仅支持通过 SIGHUP 重新加载配置", err)
+		}
+	} else {
+		defer watcher.Close()
+		reloadChan = watcher.Changes()
+	}
+
 	if cfg.ShowWindow {
 		color.Cyan("🚀 程序启动成功，开始监控...")
 		color.Cyan("📺 窗口显示: 已启用")
@@ -219,136 +453,466 @@ func main() {
 		fmt.Println()
 	}
 
-	lastCPUWorkerState := false
-	lastMemWorkerState := false
+	var dashboard *tui.Dashboard
+	if *tuiMode {
+		dashboard = tui.New(ctrl, cpuMonitor.GetPerCoreUsage, tray.RequestQuit)
+		if err := dashboard.Start(); err != nil {
+			slog.Warn("终端仪表盘启动失败", "error", err)
+			color.Yellow("⚠ %v", err)
+			dashboard = nil
+		} else {
+			defer dashboard.Stop()
+		}
+	}
 
 	for {
 		select {
 		case <-ticker.C:
-			cpuUsage, err := cpuMonitor.GetUsage()
+			snap, err := ctrl.Tick()
 			if err != nil {
+				slog.Error("监控采样失败", "error", err)
+				if collector != nil {
+					collector.RecordError()
+				}
 				if cfg.ShowWindow {
-					color.Red("✗ 获取CPU使用率失败: %v", err)
+					color.Red("✗ %v", err)
+				}
+				if dashboard != nil {
+					dashboard.Notice("监控采样失败: %v", err)
 				}
-				continue
 			}
-
-			memUsage, err := memMonitor.GetUsage()
-			if err != nil {
-				if cfg.ShowWindow {
-					color.Red("✗ 获取内存使用率失败: %v", err)
+			logEvents(snap.Events)
+			if collector != nil {
+				collector.Record(snap)
+			}
+			if recorder != nil {
+				if err := recorder.Record(snap); err != nil {
+					log.Printf("%v", err)
 				}
-				continue
 			}
 
 			if cfg.ShowWindow {
-				displayMonitorInfo(cpuUsage, memUsage, cfg, cpuWorker, memWorker)
+				displayMonitorInfo(snap, registry, tracker)
+				displayEvents(snap.Events, registry)
+			}
+			if dashboard != nil {
+				dashboard.Update(snap)
 			}
 
-			if versionValid && cpuWorker != nil && memWorker != nil {
-				cpuWorkerUsage := cpuWorker.GetUsage()
-				memWorkerUsage := memWorker.GetUsage()
-
-				otherCPUUsage := cpuUsage - cpuWorkerUsage
-				otherMemUsage := memUsage - memWorkerUsage
-
-				if otherCPUUsage < 0 {
-					otherCPUUsage = 0
-				}
-				if otherMemUsage < 0 {
-					otherMemUsage = 0
-				}
-
-				shouldCPUWork := otherCPUUsage < float64(cfg.CPUThreshold)
-				if shouldCPUWork != lastCPUWorkerState {
-					if shouldCPUWork {
-						cpuWorker.Start()
-						if cfg.ShowWindow {
-							color.Green("✓ [CPU] 其他程序占用 %.1f%% < 阈值 %d%%，开始CPU计算", otherCPUUsage, cfg.CPUThreshold)
-						}
-						notifier.NotifyCPUWorkStart(cfg.CPUThreshold)
-					} else {
-						cpuWorker.Stop()
-						if cfg.ShowWindow {
-							color.Yellow("⚠ [CPU] 其他程序占用 %.1f%% >= 阈值 %d%%，停止CPU计算", otherCPUUsage, cfg.CPUThreshold)
-						}
-						notifier.NotifyCPUWorkStop()
-					}
-					lastCPUWorkerState = shouldCPUWork
-				}
-
-				if shouldCPUWork {
-					targetCPUWorkerUsage := float64(cfg.CPUThreshold) - otherCPUUsage
-					if targetCPUWorkerUsage < 0 {
-						targetCPUWorkerUsage = 0
-					}
-					if targetCPUWorkerUsage > 100 {
-						targetCPUWorkerUsage = 100
-					}
-					cpuWorker.AdjustLoad(cpuWorkerUsage, targetCPUWorkerUsage)
-				}
-
-				shouldMemWork := otherMemUsage < float64(cfg.MemoryThreshold)
-				if shouldMemWork != lastMemWorkerState {
-					if shouldMemWork {
-						memWorker.Start()
-						if cfg.ShowWindow {
-							color.Green("✓ [MEM] 其他程序占用 %.1f%% < 阈值 %d%%，开始内存计算", otherMemUsage, cfg.MemoryThreshold)
-						}
-						notifier.NotifyMemWorkStart(cfg.MemoryThreshold)
-					} else {
-						memWorker.Stop()
-						if cfg.ShowWindow {
-							color.Yellow("⚠ [MEM] 其他程序占用 %.1f%% >= 阈值 %d%%，停止内存计算", otherMemUsage, cfg.MemoryThreshold)
-						}
-						notifier.NotifyMemWorkStop()
-					}
-					lastMemWorkerState = shouldMemWork
-				}
+		case <-reloadChan:
+			cfg, tracker = reloadConfig(cfgPath, cfg, tracker, ctrl, ticker, notifier, limiter)
 
-				if shouldMemWork {
-					targetMemWorkerUsage := float64(cfg.MemoryThreshold) - otherMemUsage
-					if targetMemWorkerUsage < 0 {
-						targetMemWorkerUsage = 0
-					}
-					if targetMemWorkerUsage > 80 {
-						targetMemWorkerUsage = 80
-					}
-					memWorker.AdjustLoad(memWorkerUsage, targetMemWorkerUsage)
-				}
+		case <-hupChan:
+			if cfg.ShowWindow {
+				color.Cyan("📡 接收到 SIGHUP，重新加载配置...")
 			}
+			cfg, tracker = reloadConfig(cfgPath, cfg, tracker, ctrl, ticker, notifier, limiter)
 
 		case <-sigChan:
 			if cfg.ShowWindow {
 				color.Cyan("📡 接收到退出信号，正在清理...")
 			}
-			if cpuWorker != nil {
-				cpuWorker.Stop()
+			slog.Info("程序退出", "reason", "signal")
+			ctrl.Shutdown()
+			if cfg.ShowWindow {
+				color.Green("✓ 程序已安全退出")
 			}
-			if memWorker != nil {
-				memWorker.Stop()
+			return
+
+		case <-trayQuitChan:
+			if cfg.ShowWindow {
+				color.Cyan("📡 从托盘或控制接口接收到退出请求，正在清理...")
 			}
+			slog.Info("程序退出", "reason", "quit request")
+			ctrl.Shutdown()
 			if cfg.ShowWindow {
 				color.Green("✓ 程序已安全退出")
 			}
 			return
+		}
+	}
+}
 
-		case <-trayQuitChan:
+// applyFlagOverrides 用命令行参数覆盖配置，重新加载配置后同样需要覆盖
+func applyFlagOverrides(cfg *config.Config, verbose bool) {
+	if *cpuThreshold >= 0 {
+		cfg.CPUThreshold = *cpuThreshold
+		if verbose {
+			color.Yellow("⚙️  命令行参数覆盖: CPU阈值 = %d%%", cfg.CPUThreshold)
+		}
+	}
+
+	if *memThreshold >= 0 {
+		cfg.MemoryThreshold = *memThreshold
+		if verbose {
+			color.Yellow("⚙️  命令行参数覆盖: 内存阈值 = %d%%", cfg.MemoryThreshold)
+		}
+	}
+
+	if *showWindow != "" {
+		switch *showWindow {
+		case "true", "1", "yes", "on":
+			cfg.ShowWindow = true
+			if verbose {
+				color.Yellow("⚙️  命令行参数覆盖: 显示窗口 = true")
+			}
+		case "false", "0", "no", "off":
+			cfg.ShowWindow = false
+			if verbose {
+				color.Yellow("⚙️  命令行参数覆盖: 显示窗口 = false")
+			}
+		default:
+			if verbose {
+				color.Yellow("⚠ 无效的 -window 参数值: %s (使用 true 或 false)", *showWindow)
+			}
+		}
+	}
+
+	// 仪表盘独占终端，滚动输出和控制台日志会破坏画面
+	if *tuiMode {
+		cfg.ShowWindow = false
+	}
+}
+
+// useCgroup 按配置让监控使用 cgroup v2 的用量和上限计算占用
+func useCgroup(cfg *config.Config, cpuMonitor *monitor.CPUMonitor, memMonitor *monitor.MemoryMonitor) {
+	if cfg.Cgroup.Mode == config.CgroupModeHost {
+		return
+	}
+
+	force := cfg.Cgroup.Mode == config.CgroupModeCgroup
+	group, err := cgroup.Detect()
+	if err != nil {
+		if force {
+			slog.Warn("未检测到 cgroup v2，按整机计算占用", "error", err)
 			if cfg.ShowWindow {
-				color.Cyan("📡 从托盘接收到退出信号，正在清理...")
+				color.Yellow("⚠ 未检测到 cgroup v2，按整机计算占用: %v", err)
 			}
-			if cpuWorker != nil {
-				cpuWorker.Stop()
+		}
+		return
+	}
+
+	cores, cpuLimited := group.CPULimit()
+	memLimit, memLimited := group.MemoryLimit()
+	if force || cpuLimited {
+		cpuMonitor.UseCgroup(group)
+	}
+	if force || memLimited {
+		memMonitor.UseCgroup(group)
+	}
+	if !force && !cpuLimited && !memLimited {
+		return
+	}
+
+	slog.Info("按 cgroup 计算占用", "dir", group.Dir, "cpu_limit", cores, "memory_limit", memLimit)
+	if cfg.ShowWindow {
+		color.Cyan("📦 按 cgroup 计算占用: %s", group.Dir)
+		if cpuLimited {
+			color.Cyan("   CPU 上限: %.2f 核", cores)
+		}
+		if memLimited {
+			color.Cyan("   内存上限: %d MB", memLimit/1024/1024)
+		}
+	}
+}
+
+// minCgroupMemory 专用 cgroup 内存上限的下限，保证监控本身可以运行
+const minCgroupMemory = 64 * 1024 * 1024
+
+// cgroupLimiter 按阈值设置专用 cgroup 的上限
+type cgroupLimiter struct {
+	group  *cgroup.Group
+	cores  float64 // CPU 占用率 100% 对应的核心数
+	memory uint64  // 内存占用率 100% 对应的字节数
+}
+
+// confineCgroup 设置专用 cgroup 的上限后将进程移入，失败时返回 nil，进程留在原 cgroup
+func confineCgroup(cfg *config.Config, cpuMonitor *monitor.CPUMonitor, memMonitor *monitor.MemoryMonitor) *cgroupLimiter {
+	total, err := memMonitor.GetTotalMemory()
+	if err == nil {
+		limiter := &cgroupLimiter{cores: cpuMonitor.GetCapacity(), memory: total}
+		var group *cgroup.Group
+		group, err = cgroup.Join(cfg.Cgroup.Path, func(g *cgroup.Group) error {
+			limiter.group = g
+			return limiter.apply(cfg)
+		})
+		if err == nil {
+			slog.Info("已加入专用 cgroup", "dir", group.Dir)
+			if cfg.ShowWindow {
+				color.Cyan("🔒 专用 cgroup: %s", group.Dir)
 			}
-			if memWorker != nil {
-				memWorker.Stop()
+			return limiter
+		}
+	}
+
+	slog.Warn("无法使用专用 cgroup", "error", err)
+	if cfg.ShowWindow {
+		color.Yellow("⚠ 无法使用专用 cgroup: %v", err)
+	}
+	return nil
+}
+
+// apply 按阈值更新上限，取全局阈值与定时计划中的最大值，运行时设置的阈值不会提高上限
+// cpu.max 为 CPU 阈值，memory.high 为内存阈值，memory.max 比内存阈值高 5%
+func (l *cgroupLimiter) apply(cfg *config.Config) error {
+	if l == nil {
+		return nil
+	}
+
+	cpu, mem := cfg.CPUThreshold, cfg.MemoryThreshold
+	for _, s := range cfg.Schedules {
+		if s.CPU != nil && *s.CPU > cpu {
+			cpu = *s.CPU
+		}
+		if s.Memory != nil && *s.Memory > mem {
+			mem = *s.Memory
+		}
+	}
+
+	// 阈值为 0 时仍保留最低配额，否则 cpu.max 会被视为不限制
+	cores := math.Max(l.cores*float64(cpu)/100, 0.01)
+	memHigh := l.memory / 100 * uint64(mem)
+	memMax := l.memory / 100 * uint64(min(mem+5, 100))
+	if memHigh < minCgroupMemory {
+		memHigh = minCgroupMemory
+	}
+	if memMax < minCgroupMemory {
+		memMax = minCgroupMemory
+	}
+
+	if err := l.group.SetCPUMax(cores); err != nil {
+		return err
+	}
+	if err := l.group.SetMemoryMax(memMax); err != nil {
+		return err
+	}
+	if err := l.group.SetMemoryHigh(memHigh); err != nil {
+		return err
+	}
+
+	slog.Info("已更新 cgroup 上限", "cpu_cores", cores, "memory_high", memHigh, "memory_max", memMax)
+	return nil
+}
+
+// workerPriority 根据配置返回工作线程的调度优先级
+func workerPriority(cfg *config.Config) worker.Priority {
+	p := worker.Priority{IOIdle: cfg.Priority.IOIdle}
+	switch cfg.Priority.CPU {
+	case config.PriorityNice:
+		p.Policy = worker.SchedNice
+	case config.PriorityIdle:
+		p.Policy = worker.SchedIdle
+	}
+	return p
+}
+
+// cpuWorkloads 配置中的 CPU 计算类型，未填写时为混合运算
+var cpuWorkloads = map[string]worker.Workload{
+	config.WorkloadMix:      worker.WorkloadMix,
+	config.WorkloadInteger:  worker.WorkloadInteger,
+	config.WorkloadFloat:    worker.WorkloadFloat,
+	config.WorkloadSHA256:   worker.WorkloadSHA256,
+	config.WorkloadCompress: worker.WorkloadCompress,
+	config.WorkloadMemory:   worker.WorkloadMemory,
+	config.WorkloadVector:   worker.WorkloadVector,
+}
+
+// buildPolicies 根据配置创建阈值策略，tracker 不为空时复用已有的合规历史
+func buildPolicies(cfg *config.Config, cfgPath string, tracker *compliance.Tracker) ([]controller.Policy, *compliance.Tracker, error) {
+	var policies []controller.Policy
+	if len(cfg.Schedules) > 0 {
+		// 计划先于合规模式作用，计划停止的时段合规模式也不补充负载
+		sched, err := schedule.New(cfg.Schedules)
+		if err != nil {
+			return nil, nil, fmt.Errorf("加载计划失败: %w", err)
+		}
+		policies = append(policies, sched)
+
+		if cfg.ShowWindow {
+			color.Cyan("📅 定时计划: %d 条规则", len(sched.Rules()))
+			for _, r := range sched.Rules() {
+				color.Cyan("  %s: %s", r.Name, r.Window())
 			}
+		}
+	}
+
+	if cfg.Compliance.Mode != config.ComplianceModeP95 {
+		return policies, nil, nil
+	}
+
+	if tracker == nil {
+		statePath := cfg.Compliance.StateFile
+		if statePath == "" {
+			statePath = compliance.DefaultStatePath(cfgPath)
+		}
+
+		floors := map[string]int{
+			resource.CPU:     cfg.Compliance.CPUFloor,
+			resource.Memory:  cfg.Compliance.MemoryFloor,
+			resource.Network: cfg.Compliance.NetworkFloor,
+		}
+		window := time.Duration(cfg.Compliance.WindowHours) * time.Hour
+
+		var err error
+		tracker, err = compliance.NewTracker(statePath, window, floors, cfg.Compliance.Margin)
+		if err != nil && cfg.ShowWindow {
+			color.Yellow("⚠ %v，将重新记录", err)
+		}
+
+		if cfg.ShowWindow {
+			color.Cyan("📊 合规模式: p95 (窗口 %d 小时)", cfg.Compliance.WindowHours)
+			color.Cyan("  历史文件: %s", statePath)
+		}
+	}
+	policies = append(policies, tracker)
+
+	return policies, tracker, nil
+}
+
+// reloadConfig 重新加载配置文件并应用到控制器、工作器、定时器和通知
+// 新配置无效时保留当前配置，返回值为实际生效的配置和合规跟踪器
+func reloadConfig(
+	cfgPath string,
+	cfg *config.Config,
+	tracker *compliance.Tracker,
+	ctrl *controller.Controller,
+	ticker *time.Ticker,
+	notifier *notify.Notifier,
+	limiter *cgroupLimiter,
+) (*config.Config, *compliance.Tracker) {
+	next, err := config.ReloadConfig(cfgPath)
+	if err == nil {
+		applyFlagOverrides(next, false)
+		err = config.ValidateConfig(next)
+	}
+	if err != nil {
+		slog.Error("重新加载配置失败，继续使用当前配置", "config", cfgPath, "error", err)
+		if cfg.ShowWindow {
+			color.Red("✗ 重新加载配置失败，继续使用当前配置: %v", err)
+		}
+		notifier.NotifyError(fmt.Sprintf("重新加载配置失败: %v", err))
+		return cfg, tracker
+	}
+
+	// 窗口模式和工作器开关在启动时确定，不随配置变化
+	next.ShowWindow = cfg.ShowWindow
+	next.EnableWorker = cfg.EnableWorker
+
+	// 合规设置未变化时沿用已有跟踪器，保留当前时间桶
+	reuse := tracker
+	if tracker != nil && next.Compliance != cfg.Compliance {
+		if err := tracker.Save(); err != nil {
+			slog.Error("保存占用历史失败", "error", err)
 			if cfg.ShowWindow {
-				color.Green("✓ 程序已安全退出")
+				color.Red("✗ 保存占用历史失败: %v", err)
 			}
-			return
 		}
+		reuse = nil
 	}
+
+	policies, nextTracker, err := buildPolicies(next, cfgPath, reuse)
+	if err != nil {
+		slog.Error("重新加载配置失败，继续使用当前配置", "config", cfgPath, "error", err)
+		if cfg.ShowWindow {
+			color.Red("✗ 重新加载配置失败，继续使用当前配置: %v", err)
+		}
+		notifier.NotifyError(fmt.Sprintf("重新加载配置失败: %v", err))
+		return cfg, tracker
+	}
+
+	ctrl.Apply(next, policies)
+	if err := limiter.apply(next); err != nil {
+		slog.Error("更新 cgroup 上限失败", "error", err)
+		if next.ShowWindow {
+			color.Red("✗ 更新 cgroup 上限失败: %v", err)
+		}
+	}
+	ticker.Reset(time.Duration(next.UpdateInterval) * time.Second)
+	notifier.SetEnabled(next.Notification.Enabled)
+	notifier.SetCooldown(next.Notification.Cooldown)
+
+	if res, ok := ctrl.Registry().Get(resource.CPU); ok {
+		if w, ok := res.Loader.(*worker.CPUWorker); ok {
+			w.SetPIDGains(next.Control.CPU.Kp, next.Control.CPU.Ki, next.Control.CPU.Kd)
+		}
+	}
+	if res, ok := ctrl.Registry().Get(resource.Memory); ok {
+		if w, ok := res.Loader.(*worker.MemoryWorker); ok {
+			w.SetPIDGains(next.Control.Memory.Kp, next.Control.Memory.Ki, next.Control.Memory.Kd)
+		}
+	}
+
+	pending := restartRequired(cfg, next)
+	slog.Info("配置已重新加载", "config", cfgPath, "pending_restart", pending)
+	if cfg.ShowWindow {
+		color.Green("✓ 配置已重新加载")
+		if len(pending) > 0 {
+			color.Yellow("⚠ 以下设置需要重启后生效: %s", strings.Join(pending, ", "))
+		}
+	}
+	return next, nextTracker
+}
+
+// logPath 返回日志文件路径
+func logPath(cfg *config.Config, cfgPath string) string {
+	if cfg.Log.Path != "" {
+		return cfg.Log.Path
+	}
+	return logger.DefaultPath(cfgPath)
+}
+
+// logEvents 将工作器启停事件写入日志
+func logEvents(events []controller.Event) {
+	for _, e := range events {
+		msg := "工作器停止"
+		if e.Started {
+			msg = "工作器启动"
+		}
+		slog.Info(msg, "resource", e.Resource, "other_usage", math.Round(e.OtherUsage*10)/10, "threshold", e.Threshold)
+	}
+}
+
+// historyPath 返回历史文件路径
+func historyPath(cfg *config.Config, cfgPath string) string {
+	if cfg.History.Path != "" {
+		return cfg.History.Path
+	}
+	return history.DefaultPath(cfgPath)
+}
+
+// restartSettings 只在启动时生效的设置，重新加载配置时检查是否变化
+var restartSettings = []struct {
+	name    string
+	changed func(old, next *config.Config) bool
+}{
+	{"cpu", func(old, next *config.Config) bool { return old.CPU != next.CPU }},
+	{"memory", func(old, next *config.Config) bool { return old.Memory != next.Memory }},
+	{"priority", func(old, next *config.Config) bool { return old.Priority != next.Priority }},
+	{"cgroup", func(old, next *config.Config) bool { return old.Cgroup != next.Cgroup }},
+	{"pressure", func(old, next *config.Config) bool { return old.Pressure != next.Pressure }},
+	{"network", func(old, next *config.Config) bool {
+		return old.NetworkEnabled() != next.NetworkEnabled() || !reflect.DeepEqual(old.Network, next.Network)
+	}},
+	{"disk", func(old, next *config.Config) bool {
+		return old.DiskEnabled() != next.DiskEnabled() || !reflect.DeepEqual(old.Disk, next.Disk)
+	}},
+	{"api", func(old, next *config.Config) bool { return old.API != next.API }},
+	{"metrics", func(old, next *config.Config) bool { return old.Metrics != next.Metrics }},
+	{"history", func(old, next *config.Config) bool { return old.History != next.History }},
+	{"log", func(old, next *config.Config) bool { return old.Log != next.Log }},
+	{"auto_start", func(old, next *config.Config) bool { return old.AutoStart != next.AutoStart }},
+	{"update_check", func(old, next *config.Config) bool { return old.UpdateCheck != next.UpdateCheck }},
+}
+
+// restartRequired 返回发生变化但只在启动时生效的设置
+func restartRequired(old, next *config.Config) []string {
+	var pending []string
+	for _, s := range restartSettings {
+		if s.changed(old, next) {
+			pending = append(pending, s.name)
+		}
+	}
+	return pending
 }
 
 // checkUpdateOnStartup 启动时检查更新
@@ -359,14 +923,17 @@ func checkUpdateOnStartup(cfg *config.Config) {
 	release, hasUpdate, err := upd.CheckUpdateSilent()
 	if err != nil {
 		// 静默失败，不显示错误
+		slog.Warn("检查更新失败", "error", err)
 		return
 	}
 
 	if hasUpdate {
+		slog.Info("发现新版本", "current", version.GetVersion(), "latest", release.TagName)
 		if !cfg.UpdateCheck.SilentCheck {
 			updater.ShowUpdateNotice(release, version.GetVersion())
 		}
 	} else {
+		slog.Info("当前已是最新版本", "version", version.GetVersion())
 		if !cfg.UpdateCheck.SilentCheck && cfg.ShowWindow {
 			color.Green("✓ 当前已是最新版本 v%s", version.GetVersion())
 			fmt.Println()
@@ -470,6 +1037,12 @@ func showWelcome(cfg *config.Config) {
 
 	color.New(color.FgHiCyan).Printf("⚙️  CPU阈值: %d%%\n", cfg.CPUThreshold)
 	color.New(color.FgHiCyan).Printf("⚙️  内存阈值: %d%%\n", cfg.MemoryThreshold)
+	if cfg.NetworkThreshold > 0 {
+		color.New(color.FgHiCyan).Printf("⚙️  网络阈值: %d Mbps\n", cfg.NetworkThreshold)
+	}
+	if cfg.DiskThreshold > 0 {
+		color.New(color.FgHiCyan).Printf("⚙️  磁盘阈值: %d MB/s\n", cfg.DiskThreshold)
+	}
 	color.New(color.FgHiCyan).Printf("⚙️  窗口模式: %s\n", getWindowModeText(cfg.ShowWindow))
 
 	enabled, err := autostart.IsEnabled()
@@ -491,47 +1064,82 @@ func getWindowModeText(showWindow bool) string {
 	return "隐藏 (后台运行)"
 }
 
-func displayMonitorInfo(cpuUsage, memUsage float64, cfg *config.Config, cpuWorker *worker.CPUWorker, memWorker *worker.MemoryWorker) {
-	timestamp := time.Now().Format("15:04:05")
+// workerColors 各资源工作器状态的显示颜色，按注册顺序循环使用
+var workerColors = []color.Attribute{color.FgCyan, color.FgMagenta, color.FgBlue, color.FgHiYellow}
 
-	cpuColor := color.New(color.FgGreen)
-	if cpuUsage > float64(cfg.CPUThreshold) {
-		cpuColor = color.New(color.FgRed)
-	} else if cpuUsage > float64(cfg.CPUThreshold)*0.8 {
-		cpuColor = color.New(color.FgYellow)
-	}
+func displayMonitorInfo(snap controller.Snapshot, registry *resource.Registry, tracker *compliance.Tracker) {
+	timestamp := snap.Time.Format("15:04:05")
+
+	fmt.Printf("[%s]", timestamp)
 
-	memColor := color.New(color.FgGreen)
-	if memUsage > float64(cfg.MemoryThreshold) {
-		memColor = color.New(color.FgRed)
-	} else if memUsage > float64(cfg.MemoryThreshold)*0.8 {
-		memColor = color.New(color.FgYellow)
+	for _, state := range snap.Resources {
+		res, ok := registry.Get(state.Name)
+		if !ok {
+			continue
+		}
+
+		usageColor := color.New(color.FgGreen)
+		if state.Usage > float64(state.Threshold) {
+			usageColor = color.New(color.FgRed)
+		} else if state.Usage > float64(state.Threshold)*0.8 {
+			usageColor = color.New(color.FgYellow)
+		}
+		usageColor.Printf(" %s: %5.1f%s", res.Tag, state.Usage, res.Unit)
 	}
 
-	fmt.Printf("[%s] ", timestamp)
-	cpuColor.Printf("CPU: %5.1f%% ", cpuUsage)
-	memColor.Printf("MEM: %5.1f%%", memUsage)
+	for i, state := range snap.Resources {
+		res, ok := registry.Get(state.Name)
+		if !ok || res.Loader == nil {
+			continue
+		}
 
-	if cpuWorker != nil {
-		if cpuWorker.IsRunning() {
-			color.New(color.FgCyan).Printf(" [CPU-W: ON, I:%d%%]", cpuWorker.GetIntensity())
+		if state.Paused {
+			color.New(color.FgHiBlack).Printf(" [%s-W: PAUSED]", res.Tag)
+		} else if res.Loader.IsRunning() {
+			detail := ""
+			if reporter, ok := res.Loader.(worker.Reporter); ok {
+				detail = ", " + reporter.ShortStatus()
+			}
+			color.New(workerColors[i%len(workerColors)]).Printf(" [%s-W: ON%s]", res.Tag, detail)
 		} else {
-			color.New(color.FgHiBlack).Printf(" [CPU-W: OFF]")
+			color.New(color.FgHiBlack).Printf(" [%s-W: OFF]", res.Tag)
 		}
 	}
 
-	if memWorker != nil {
-		if memWorker.IsRunning() {
-			allocMB := memWorker.GetAllocatedSize() / 1024 / 1024
-			color.New(color.FgMagenta).Printf(" [MEM-W: ON, A:%dMB]", allocMB)
-		} else {
-			color.New(color.FgHiBlack).Printf(" [MEM-W: OFF]")
+	if tracker != nil {
+		for _, state := range snap.Resources {
+			floor, ok := tracker.Floor(state.Name)
+			if !ok {
+				continue
+			}
+			res, _ := registry.Get(state.Name)
+			p95, _ := tracker.Current(state.Name, snap.Time)
+			color.New(color.FgHiBlue).Printf(" [%s-P95: %.1f/%d]", res.Tag, p95, floor)
 		}
 	}
 
+	for _, note := range snap.Notes {
+		color.New(color.FgHiWhite).Printf(" [%s]", note)
+	}
+
 	fmt.Println()
 }
 
+func displayEvents(events []controller.Event, registry *resource.Registry) {
+	for _, e := range events {
+		res, ok := registry.Get(e.Resource)
+		if !ok {
+			continue
+		}
+
+		if e.Started {
+			color.Green("✓ [%s] 其他程序占用 %.1f%s < 阈值 %d%s，开始%s计算", res.Tag, e.OtherUsage, res.Unit, e.Threshold, res.Unit, res.Label)
+		} else {
+			color.Yellow("⚠ [%s] 其他程序占用 %.1f%s >= 阈值 %d%s，停止%s计算", res.Tag, e.OtherUsage, res.Unit, e.Threshold, res.Unit, res.Label)
+		}
+	}
+}
+
 func showHelpInfo() {
 	cyan := color.New(color.FgCyan, color.Bold)
 	cyan.Println("╔════════════════════════════════════════════════════════════╗")
@@ -542,6 +1150,16 @@ func showHelpInfo() {
 
 	color.New(color.FgYellow, color.Bold).Println("📖 用法:")
 	fmt.Println("  MikaBooM [选项]")
+	fmt.Println("  MikaBooM [-c <file>] <命令> [参数]")
+	fmt.Println()
+
+	color.New(color.FgYellow, color.Bold).Println("🔌 命令 (report 以外需要启用 api，连接已经运行的实例):")
+	for _, name := range commandOrder {
+		fmt.Printf("  MikaBooM %s\n", commands[name].usage)
+	}
+	fmt.Println("                      pause/resume 不指定资源时作用于所有工作器")
+	fmt.Println("                      set 的阈值为 default 时恢复使用配置文件中的值")
+	fmt.Println("                      report 读取历史记录，输出平均值、百分位、超过阈值时长和额外负载")
 	fmt.Println()
 
 	color.New(color.FgYellow, color.Bold).Println("⚙️  选项:")
@@ -556,6 +1174,10 @@ func showHelpInfo() {
 	fmt.Println("                      false/0/no/off - 隐藏窗口（后台运行）")
 	fmt.Println("                      示例: -window=false")
 	fmt.Println()
+	fmt.Println("  -tui                全屏终端仪表盘")
+	fmt.Println("                      显示占用曲线、每核心占用、工作器强度和当前计划")
+	fmt.Println("                      按键: ↑/↓ 选择资源, p 暂停/恢复, +/- 调整阈值, r 恢复配置阈值, q 退出")
+	fmt.Println()
 	fmt.Println("  -auto               启用开机自启动")
 	fmt.Println()
 	fmt.Println("  -noauto             禁用开机自启动")
@@ -584,6 +1206,9 @@ func showHelpInfo() {
 	fmt.Println("  # 后台运行，不显示窗口")
 	fmt.Println("  MikaBooM -window=false")
 	fmt.Println()
+	fmt.Println("  # 全屏终端仪表盘")
+	fmt.Println("  MikaBooM -tui")
+	fmt.Println()
 	fmt.Println("  # 启用开机自启动")
 	fmt.Println("  MikaBooM -auto")
 	fmt.Println()
@@ -630,6 +1255,8 @@ func showHelpInfo() {
 	fmt.Println("  支持的配置项:")
 	fmt.Println("    - cpu_threshold      CPU阈值 (0-100)")
 	fmt.Println("    - memory_threshold   内存阈值 (0-100)")
+	fmt.Println("    - network_threshold  网络发送带宽阈值 (Mbps, 0 为禁用)")
+	fmt.Println("    - disk_threshold     磁盘读写吞吐量阈值 (MB/s, 0 为禁用)")
 	fmt.Println("    - show_window        是否显示窗口 (true/false)")
 	fmt.Println("    - auto_start         是否自启动 (true/false)")
 	fmt.Println("    - update_interval    更新间隔（秒）")
@@ -640,12 +1267,88 @@ func showHelpInfo() {
 	fmt.Println("      - enabled          是否启用更新检查")
 	fmt.Println("      - check_on_startup 是否启动时检查")
 	fmt.Println("      - silent_check     是否静默检查")
+	fmt.Println("    - control            负载调节控制器 (PID) 增益")
+	fmt.Println("      - cpu/memory       kp / ki / kd")
+	fmt.Println("    - cpu                CPU 负载设置")
+	fmt.Println("      - per_core         工作线程绑定核心，按各核心占用调节 (Linux)")
+	fmt.Println("      - workload         mix / integer / float / sha256 / compress / memory / vector")
+	fmt.Println("      - memory_kernel_mb memory 计算类型每个工作线程使用的内存 (MB)")
+	fmt.Println("    - memory             内存负载设置")
+	fmt.Println("      - allocator        mmap (释放时立即归还系统) / heap (Go 堆)")
+	fmt.Println("      - fill             random (伪随机数据，无法合并或压缩) / sparse")
+	fmt.Println("      - touch_interval   定期改写页面的间隔 (秒)，0 为不改写")
+	fmt.Println("      - mlock            锁定内存，禁止换出")
+	fmt.Println("      - oom_score_adj    启动时写入的 OOM 评分，0 为不修改 (Linux)")
+	fmt.Println("      - min_available    系统可用内存下限 (MB)，低于时释放全部内存")
+	fmt.Println("    - priority           工作线程调度优先级 (Linux)")
+	fmt.Println("      - cpu              normal / nice / idle (SCHED_IDLE)")
+	fmt.Println("      - io_idle          I/O 优先级设为 idle")
+	fmt.Println("    - cgroup             容器资源限制 (cgroup v2, Linux)")
+	fmt.Println("      - mode             auto / host / cgroup")
+	fmt.Println("      - confine          在专用 cgroup 中运行，按阈值设置 cpu.max / memory.high / memory.max")
+	fmt.Println("      - path             专用 cgroup 路径")
+	fmt.Println("    - pressure           资源压力紧急退让 (PSI, Linux)")
+	fmt.Println("      - memory_some      内存 some 停顿上限 (%)，超过时立即释放内存")
+	fmt.Println("      - memory_full      内存 full 停顿上限 (%)")
+	fmt.Println("      - cpu_some         CPU some 停顿上限 (%)，超过时立即停止加负载")
+	fmt.Println("    - network            网络负载设置")
+	fmt.Println("      - endpoint         流量目标地址 (host:port)")
+	fmt.Println("      - sink             是否启动本地接收端")
+	fmt.Println("      - interface        统计带宽的网卡")
+	fmt.Println("      - max_mbps         发送带宽上限")
+	fmt.Println("    - disk               磁盘负载设置")
+	fmt.Println("      - directory        临时文件目录")
+	fmt.Println("      - file_size_mb     临时文件大小上限")
+	fmt.Println("      - direct_io        是否使用 O_DIRECT")
+	fmt.Println("      - devices          统计吞吐量的设备")
+	fmt.Println("      - max_mbps         吞吐量上限")
+	fmt.Println("    - compliance         闲置回收合规模式")
+	fmt.Println("      - mode             threshold / p95")
+	fmt.Println("      - window_hours     滚动窗口长度（小时）")
+	fmt.Println("      - cpu_floor        CPU p95 下限")
+	fmt.Println("      - memory_floor     内存 p95 下限")
+	fmt.Println("      - network_floor    网络 p95 下限 (Mbps)")
+	fmt.Println("      - margin           补充负载时高出下限的余量")
+	fmt.Println("    - api                本地控制接口")
+	fmt.Println("      - enabled          是否启用")
+	fmt.Println("      - listen           监听地址 (127.0.0.1:port 或 unix:/path)")
+	fmt.Println("      - token            访问令牌")
+	fmt.Println("    - metrics            Prometheus 指标")
+	fmt.Println("      - enabled          是否启用")
+	fmt.Println("      - listen           监听地址")
+	fmt.Println("      - path             指标路径")
+	fmt.Println("    - history            占用历史记录")
+	fmt.Println("      - enabled          是否启用")
+	fmt.Println("      - path             历史文件路径")
+	fmt.Println("      - interval         汇总写入间隔（秒）")
+	fmt.Println("      - max_size_mb      单个文件大小上限")
+	fmt.Println("      - max_files        保留的轮转文件数")
+	fmt.Println("    - log                日志文件")
+	fmt.Println("      - enabled          是否写入日志文件")
+	fmt.Println("      - level            debug / info / warn / error")
+	fmt.Println("      - format           text / json")
+	fmt.Println("      - path             日志文件路径")
+	fmt.Println("      - max_size_mb      单个文件大小上限")
+	fmt.Println("      - max_files        保留的轮转文件数")
+	fmt.Println("      - max_age_days     轮转文件保留天数")
+	fmt.Println("    - schedules          定时计划，第一条命中的规则生效")
+	fmt.Println("      - name             规则名称")
+	fmt.Println("      - days             mon..sun / weekdays / weekends / daily")
+	fmt.Println("      - start / end      时间段 (HH:MM，可跨越午夜)")
+	fmt.Println("      - off              停止所有负载")
+	fmt.Println("      - cpu/memory/...   覆盖对应阈值")
 	fmt.Println()
 
 	color.New(color.FgMagenta, color.Bold).Println("🔧 配置优先级:")
 	fmt.Println("  命令行参数 > 配置文件")
 	fmt.Println("  示例: 配置文件中 cpu_threshold=70，命令行使用 -cpu 80")
 	fmt.Println("        最终使用 CPU阈值=80%")
+	fmt.Println("  修改配置文件后自动重新加载（也可发送 SIGHUP），命令行参数仍然优先")
+	names := make([]string, len(restartSettings))
+	for i, setting := range restartSettings {
+		names[i] = setting.name
+	}
+	fmt.Printf("  %s 需要重启后生效\n", strings.Join(names, "、"))
 	fmt.Println()
 
 	color.New(color.FgCyan, color.Bold).Println("📂 配置文件示例:")